@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/taxonomy"
+	"github.com/go-logr/logr"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+type ApplyCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	Prune  bool
+	Yes    bool
+	DryRun bool
+}
+
+const applyDesc = `Reads a YAML manifest of tags, correspondents, document types, storage paths and custom fields and reconciles the Paperless instance to it.
+Missing entities are created and changed ones updated, matched by name.
+Fields that are omitted in the manifest are left unchanged on the server.
+With --%s, entities that are not in the manifest are deleted, but only for the kinds that are listed in the manifest.
+
+Example manifest:
+
+  tags:
+    - name: Invoice
+      match: invoice bill
+      matching_algorithm: any
+      case_insensitive: true
+      color: "#a6cee3"
+    - name: Inbox
+      inbox_tag: true
+  correspondents:
+    - name: Bank
+  document_types:
+    - name: Letter
+  storage_paths:
+    - name: By year
+      path: "{created_year}/{title}"
+  custom_fields:
+    - name: Amount
+      data_type: monetary`
+
+func newApplyCommand() *ApplyCommand {
+	c := &ApplyCommand{}
+	c.Command = cli.Command{
+		Name:        "apply",
+		Usage:       "Reconciles tags, correspondents and other entities to a manifest",
+		Description: fmt.Sprintf(applyDesc, newPruneFlag(nil).Name),
+		Before:      before(requireArgs, loadConfigFileFn),
+		Action:      actions(LogMetadata, c.Action),
+		ArgsUsage:   "MANIFEST",
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newPruneFlag(&c.Prune),
+			newYesFlag(&c.Yes),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *ApplyCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	manifest, err := c.loadManifest(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	changes, err := c.plan(ctx, clt, manifest)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		log.Info("Nothing to change")
+		return printChanges(ctx, changes)
+	}
+
+	deletions := 0
+	for _, change := range changes {
+		if change.Action == taxonomy.ActionDelete {
+			deletions++
+		}
+	}
+	if c.DryRun {
+		log.Info("Would apply changes", "count", len(changes))
+		return printChanges(ctx, changes)
+	}
+	if !isStructuredOutput(ctx) {
+		// show the plan before asking for confirmation
+		if err := printChanges(ctx, changes); err != nil {
+			return err
+		}
+	}
+	if deletions > 0 {
+		confirmed, confirmErr := confirm(fmt.Sprintf("Apply %d change(s), including %d deletion(s)?", len(changes), deletions), c.Yes)
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if !confirmed {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	for _, change := range changes {
+		if applyErr := taxonomy.Apply(ctx.Context, clt, change); applyErr != nil {
+			return applyErr
+		}
+		log.V(1).Info("Applied change", "kind", change.Kind, "action", change.Action, "name", change.Name)
+	}
+	log.Info("Applied changes", "count", len(changes))
+	if isStructuredOutput(ctx) {
+		return printChanges(ctx, changes)
+	}
+	return nil
+}
+
+func (c *ApplyCommand) loadManifest(path string) (*taxonomy.Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open manifest: %w", err)
+	}
+	defer f.Close()
+	return taxonomy.LoadManifest(f)
+}
+
+// plan returns the changes of all kinds that are listed in the manifest.
+func (c *ApplyCommand) plan(ctx *cli.Context, clt *paperless.Client, manifest *taxonomy.Manifest) ([]taxonomy.Change, error) {
+	changes := make([]taxonomy.Change, 0)
+	for _, kind := range taxonomy.Kinds {
+		specs := manifest.Specs(kind)
+		if specs == nil {
+			continue
+		}
+		existing, err := taxonomy.Fetch(ctx.Context, clt, kind)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot query %ss", kind)
+		}
+		kindChanges, err := taxonomy.Plan(kind, specs, existing, c.Prune)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, kindChanges...)
+	}
+	return changes, nil
+}
+
+// printChanges prints the changes as colored diff in the text output.
+func printChanges(ctx *cli.Context, changes []taxonomy.Change) error {
+	return printResult(ctx, resultPrinter{
+		result: changes,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"Kind", "Action", "ID", "Name"}}
+			for _, change := range changes {
+				data = append(data, []string{string(change.Kind), string(change.Action), strconv.Itoa(change.ID), change.Name})
+			}
+			return data
+		},
+		text: func(w io.Writer) error {
+			for _, change := range changes {
+				printChange(w, change)
+			}
+			return nil
+		},
+	})
+}
+
+func printChange(w io.Writer, change taxonomy.Change) {
+	switch change.Action {
+	case taxonomy.ActionCreate:
+		fmt.Fprintln(w, pterm.FgGreen.Sprint(change.String()))
+	case taxonomy.ActionUpdate:
+		fmt.Fprintln(w, pterm.FgYellow.Sprint(change.String()))
+	default:
+		fmt.Fprintln(w, pterm.FgRed.Sprint(change.String()))
+	}
+}
@@ -2,15 +2,23 @@ package main
 
 import (
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ccremer/paperless-cli/pkg/archive"
 	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/layout"
 	"github.com/ccremer/paperless-cli/pkg/localdb"
 	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/safedelete"
 	"github.com/go-logr/logr"
+	"github.com/pterm/pterm"
 	"github.com/urfave/cli/v2"
 )
 
@@ -26,6 +34,53 @@ type BulkDownloadCommand struct {
 	UnzipEnabled            bool
 	OverwriteExistingTarget bool
 	Incremental             bool
+	WithContent             bool
+	FilenameFormat          string
+	ExportFormat            string
+	DryRun                  bool
+	MaxDeletions            string
+	Force                   bool
+	TrashRetention          time.Duration
+
+	layout *layout.Template
+	trash  *safedelete.Trash
+	result downloadResult
+}
+
+// downloadResult lists the documents that have been changed in the target.
+type downloadResult struct {
+	Target    string             `json:"target"`
+	DryRun    bool               `json:"dry_run,omitempty"`
+	Documents []downloadedResult `json:"documents"`
+}
+
+// downloadedResult is a single document that has been downloaded, renamed or removed.
+type downloadedResult struct {
+	ID     int      `json:"id"`
+	Title  string   `json:"title"`
+	Action string   `json:"action"`
+	Files  []string `json:"files,omitempty"`
+}
+
+const (
+	downloadActionDownloaded = "downloaded"
+	downloadActionUpdated    = "updated"
+	downloadActionRenamed    = "renamed"
+	downloadActionRemoved    = "removed"
+)
+
+func (r *downloadResult) add(doc paperless.Document, action string, files ...string) {
+	r.Documents = append(r.Documents, downloadedResult{ID: doc.ID, Title: doc.Title, Action: action, Files: files})
+}
+
+// setFiles sets the files of the last added result of the given document.
+func (r *downloadResult) setFiles(id int, files []string) {
+	for i := len(r.Documents) - 1; i >= 0; i-- {
+		if r.Documents[i].ID == id {
+			r.Documents[i].Files = files
+			return
+		}
+	}
 }
 
 const desc = `Use this command to create a local offline-copy of all documents.
@@ -48,67 +103,97 @@ func newBulkDownloadCommand() *BulkDownloadCommand {
 			newUnzipFlag(&c.UnzipEnabled),
 			newOverwriteFlag(&c.OverwriteExistingTarget),
 			newIncrementalFlag(&c.Incremental),
+			newWithContentFlag(&c.WithContent),
+			newFilenameFormatFlag(&c.FilenameFormat),
+			newExportFormatFlag(&c.ExportFormat),
+			newDryRunFlag(&c.DryRun),
+			newMaxDeletionsFlag(&c.MaxDeletions),
+			newForceFlag(&c.Force),
+			newTrashRetentionFlag(&c.TrashRetention),
 		},
 	}
 	return c
 }
 
 func (c *BulkDownloadCommand) Action(ctx *cli.Context) error {
+	c.result = downloadResult{DryRun: c.DryRun, Documents: make([]downloadedResult, 0)}
+	if err := c.download(ctx); err != nil {
+		return err
+	}
+	c.result.Target = c.getTargetPath()
+	return printResult(ctx, resultPrinter{
+		result: c.result,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"ID", "Title", "Action", "Files"}}
+			for _, doc := range c.result.Documents {
+				data = append(data, []string{strconv.Itoa(doc.ID), doc.Title, doc.Action, strings.Join(doc.Files, ", ")})
+			}
+			return data
+		},
+		// the downloaded documents are already logged
+		text: func(io.Writer) error { return nil },
+	})
+}
+
+func (c *BulkDownloadCommand) download(ctx *cli.Context) error {
 	log := logr.FromContextOrDiscard(ctx.Context)
 	if c.Incremental {
 		c.OverwriteExistingTarget = true
 		c.UnzipEnabled = true
 	}
+	if c.FilenameFormat != "" {
+		if !c.Incremental {
+			return fmt.Errorf("flag --%s requires --%s", newFilenameFormatFlag(nil).Name, newIncrementalFlag(nil).Name)
+		}
+		tmpl, parseErr := layout.Parse(c.FilenameFormat)
+		if parseErr != nil {
+			return fmt.Errorf("invalid flag --%s: %w", newFilenameFormatFlag(nil).Name, parseErr)
+		}
+		c.layout = tmpl
+	}
+	if c.ExportFormat != "" && !c.Incremental {
+		return fmt.Errorf("flag --%s requires --%s", newExportFormatFlag(nil).Name, newIncrementalFlag(nil).Name)
+	}
 
-	if prepareErr := c.prepareTarget(); prepareErr != nil {
+	if prepareErr := c.prepareTarget(ctx); prepareErr != nil {
 		return prepareErr
 	}
-	clt := paperless.NewClient(c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
 
 	log.Info("Getting list of documents")
 	documents, queryErr := clt.QueryDocuments(ctx.Context, paperless.QueryParams{
-		TruncateContent: true,
+		TruncateContent: !c.WithContent,
 		Ordering:        "id",
 		PageSize:        100,
 	})
 	if queryErr != nil {
 		return queryErr
 	}
-	documentIDs := paperless.MapToDocumentIDs(documents)
-	var db *localdb.Database
-
 	if c.Incremental {
-		log.V(1).Info("Opening DB", "dir", c.getTargetPath())
-		newDb, openErr := localdb.Open(c.getTargetPath())
-		if openErr != nil {
-			return openErr
-		}
-		db = newDb
-		newDocuments := c.filterMissingDocuments(db, documents)
-		for _, doc := range newDocuments {
-			db.Put(doc)
-		}
-
-		deletedDocuments := c.filterDeletedDocuments(db, paperless.MapToDocumentMap(documents))
-		for _, deletedDoc := range deletedDocuments {
-			db.Remove(deletedDoc)
-		}
-		if err := c.removeFiles(ctx, deletedDocuments); err != nil {
-			return fmt.Errorf("cannot delete local documents: %w", err)
-		}
-		log.Info("Cleaned up deleted documents", "count", len(deletedDocuments))
-		documentIDs = paperless.MapToDocumentIDs(newDocuments)
+		return c.syncMirror(ctx, clt, documents)
 	}
 
+	documentIDs := paperless.MapToDocumentIDs(documents)
 	if len(documentIDs) == 0 {
 		log.Info("Nothing to download")
-		if db != nil {
-			log.V(1).Info("Saving DB")
-			return db.Close()
+		return nil
+	}
+
+	for _, doc := range documents {
+		c.result.add(doc, downloadActionDownloaded)
+	}
+	if c.DryRun {
+		for _, doc := range documents {
+			log.Info("Would download document", "id", doc.ID, "title", doc.Title)
 		}
+		log.Info("Would download documents", "count", len(documentIDs), "target", c.getTargetPath())
 		return nil
 	}
 
+	log.Info("Downloading documents", "count", len(documentIDs))
 	tmpFile, err := c.downloadDocuments(ctx, clt, documentIDs)
 	if err != nil {
 		return err
@@ -116,56 +201,76 @@ func (c *BulkDownloadCommand) Action(ctx *cli.Context) error {
 	defer os.Remove(tmpFile.Name()) // cleanup if not renamed
 
 	if c.UnzipEnabled {
-		unzipErr := c.unzip(ctx, tmpFile)
+		_, unzipErr := c.unzip(ctx, tmpFile)
 		if unzipErr != nil {
 			return unzipErr
 		}
-		if db == nil {
-			return nil
-		}
-		log.V(1).Info("Saving DB")
-		return db.Close()
+		log.Info("Unzipped archive to dir", "dir", c.getTargetPath())
+		return nil
 	}
 	return c.move(ctx, tmpFile)
 }
 
-func (c *BulkDownloadCommand) removeFiles(ctx *cli.Context, deletedDocs []paperless.Document) error {
+// fetchEntities stores the tags, correspondents and document types in the DB, so that documents can be searched offline by name.
+// If an export is requested, storage paths and custom fields are fetched as well.
+// It returns all fetched entities in an otherwise empty manifest.
+func (c *BulkDownloadCommand) fetchEntities(ctx *cli.Context, clt *paperless.Client, db *localdb.Database) (*export.Manifest, error) {
 	log := logr.FromContextOrDiscard(ctx.Context)
+	log.V(1).Info("Getting list of tags, correspondents and document types")
+	m := &export.Manifest{}
+	var err error
+	if m.Tags, err = clt.QueryTags(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query tags")
+	}
+	if m.Correspondents, err = clt.QueryCorrespondents(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query correspondents")
+	}
+	if m.DocumentTypes, err = clt.QueryDocumentTypes(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query document types")
+	}
+	db.SetTags(m.Tags)
+	db.SetCorrespondents(m.Correspondents)
+	db.SetDocumentTypes(m.DocumentTypes)
+	if c.ExportFormat == "" {
+		return m, nil
+	}
+	if m.StoragePaths, err = clt.QueryStoragePaths(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query storage paths")
+	}
+	if m.CustomFields, err = clt.QueryCustomFields(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query custom fields")
+	}
+	return m, nil
+}
 
-	dir := c.getTargetPath()
+func (c *BulkDownloadCommand) removeFiles(ctx *cli.Context, db *localdb.Database, deletedDocs []paperless.Document) {
+	log := logr.FromContextOrDiscard(ctx.Context)
 
-	files := map[string]paperless.Document{}
 	for _, doc := range deletedDocs {
-		files[doc.ArchivedFileName] = doc
-		files[doc.OriginalFileName] = doc
-	}
-
-	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if entry.IsDir() {
-			return nil
+		paths := db.GetFiles(doc.ID)
+		c.result.add(doc, downloadActionRemoved, paths...)
+		if len(paths) == 0 {
+			log.Info("Cannot remove files of deleted document, their location is unknown", "id", doc.ID)
+			continue
 		}
-		fileName := filepath.Base(path)
-		if doc, found := files[fileName]; found {
+		// files that have been taken over by another document are kept
+		for _, path := range db.ReplaceFiles(doc.ID, nil) {
+			if c.DryRun {
+				log.Info("Would remove deleted document", "id", doc.ID, "title", doc.Title, "path", path)
+				continue
+			}
 			log.V(1).Info("Removing deleted document", "id", doc.ID, "path", path)
-			_ = os.Remove(path)
+			c.discardFile(ctx, path, fmt.Sprintf("document %d deleted on server", doc.ID))
 		}
-		return nil
-	})
-	return err
+	}
 }
 
 func (c *BulkDownloadCommand) downloadDocuments(ctx *cli.Context, clt *paperless.Client, documentIDs []int) (*os.File, error) {
-	log := logr.FromContextOrDiscard(ctx.Context)
-
 	tmpFile, createTempErr := os.CreateTemp(os.TempDir(), "paperless-bulk-download-")
 	if createTempErr != nil {
 		return nil, fmt.Errorf("cannot open temporary file: %w", createTempErr)
 	}
 
-	log.Info("Downloading documents", "count", len(documentIDs))
 	downloadErr := clt.BulkDownload(ctx.Context, tmpFile, paperless.BulkDownloadParams{
 		FollowFormatting: true,
 		Content:          paperless.BulkDownloadContent(c.Content),
@@ -174,20 +279,30 @@ func (c *BulkDownloadCommand) downloadDocuments(ctx *cli.Context, clt *paperless
 	return tmpFile, errors.Wrap(downloadErr, "could not download documents")
 }
 
-func (c *BulkDownloadCommand) unzip(ctx *cli.Context, tmpFile *os.File) error {
+// unzip extracts the downloaded archive into the target dir.
+// It returns the slash-separated paths of the extracted files relative to the target dir.
+func (c *BulkDownloadCommand) unzip(ctx *cli.Context, tmpFile *os.File) ([]string, error) {
 	log := logr.FromContextOrDiscard(ctx.Context)
-	downloadFilePath := c.getTargetPath()
-	if c.Content == paperless.BulkDownloadArchives.String() {
-		downloadFilePath = filepath.Join(downloadFilePath, paperless.BulkDownloadArchives.String())
+	subDir := c.unzipSubDir()
+	downloadFilePath := filepath.Join(c.getTargetPath(), subDir)
+	files, unzipErr := archive.Unzip(ctx.Context, tmpFile.Name(), downloadFilePath)
+	if unzipErr != nil {
+		return nil, fmt.Errorf("cannot unzip file %q to %q: %w", tmpFile.Name(), downloadFilePath, unzipErr)
 	}
-	if c.Content == paperless.BulkDownloadOriginal.String() {
-		downloadFilePath = filepath.Join(downloadFilePath, paperless.BulkDownloadOriginal.String())
+	log.V(1).Info("Unzipped archive to dir", "dir", downloadFilePath)
+	for i := range files {
+		files[i] = path.Join(subDir, files[i])
 	}
-	if unzipErr := archive.Unzip(ctx.Context, tmpFile.Name(), downloadFilePath); unzipErr != nil {
-		return fmt.Errorf("cannot unzip file %q to %q: %w", tmpFile.Name(), downloadFilePath, unzipErr)
+	return files, nil
+}
+
+// unzipSubDir returns the dir relative to the target path into which the archive is unzipped.
+// Paperless puts the files into "archive" and "originals" dirs only if both variants are downloaded.
+func (c *BulkDownloadCommand) unzipSubDir() string {
+	if c.Content == paperless.BulkDownloadArchives.String() || c.Content == paperless.BulkDownloadOriginal.String() {
+		return c.Content
 	}
-	log.Info("Unzipped archive to dir", "dir", downloadFilePath)
-	return nil
+	return ""
 }
 
 func (c *BulkDownloadCommand) move(ctx *cli.Context, tmpFile *os.File) error {
@@ -210,12 +325,16 @@ func (c *BulkDownloadCommand) getTargetPath() string {
 	return "documents.zip"
 }
 
-func (c *BulkDownloadCommand) prepareTarget() error {
+func (c *BulkDownloadCommand) prepareTarget(ctx *cli.Context) error {
 	target := c.getTargetPath()
 	if c.OverwriteExistingTarget {
 		if c.Incremental {
 			return nil
 		}
+		if c.DryRun {
+			logr.FromContextOrDiscard(ctx.Context).Info("Would remove existing target", "target", target)
+			return nil
+		}
 		return os.RemoveAll(target)
 	}
 	_, err := os.Stat(target)
@@ -230,7 +349,7 @@ func (c *BulkDownloadCommand) filterMissingDocuments(db *localdb.Database, docum
 	for i := 0; i < len(documentsOnServer); i++ {
 		serverDoc := documentsOnServer[i]
 		localDoc := db.FindByID(serverDoc.ID)
-		if localDoc == nil {
+		if localDoc == nil || c.isOutdated(db, *localDoc, serverDoc) {
 			missing = append(missing, serverDoc)
 		}
 	}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/archive"
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/localdb"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/safedelete"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+// variant is a downloadable version of a document, stored in its own subdirectory of the target path.
+type variant struct {
+	dir      string
+	original bool
+}
+
+var (
+	archiveVariant  = variant{dir: paperless.BulkDownloadArchives.String(), original: false}
+	originalVariant = variant{dir: paperless.BulkDownloadOriginal.String(), original: true}
+)
+
+// syncMirror updates the local mirror incrementally.
+// Removed documents are deleted locally, new and changed documents are downloaded one by one.
+// The DB is saved in any case, so that the downloaded files are tracked even if a later download fails.
+func (c *BulkDownloadCommand) syncMirror(ctx *cli.Context, clt *paperless.Client, documents []paperless.Document) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	log.V(1).Info("Opening DB", "dir", c.getTargetPath())
+	db, openErr := localdb.Open(c.getTargetPath())
+	if openErr != nil {
+		return openErr
+	}
+	manifest, fetchErr := c.fetchEntities(ctx, clt, db)
+	if fetchErr != nil {
+		return fetchErr
+	}
+
+	pendingDocuments := c.filterMissingDocuments(db, documents)
+	pending := paperless.MapToDocumentMap(pendingDocuments)
+	for _, doc := range documents {
+		// pending documents are updated once downloaded
+		if _, isPending := pending[doc.ID]; !isPending {
+			db.Put(c.toLocalDocument(doc))
+		}
+	}
+
+	deletedDocuments := c.filterDeletedDocuments(db, paperless.MapToDocumentMap(documents))
+	if checkErr := c.checkDeletions(ctx, len(deletedDocuments), len(db.GetAll())); checkErr != nil {
+		return checkErr
+	}
+	c.trash = safedelete.NewTrash(c.getTargetPath(), c.TrashRetention)
+	c.removeFiles(ctx, db, deletedDocuments)
+	for _, deletedDoc := range deletedDocuments {
+		db.Remove(deletedDoc)
+	}
+	if c.DryRun {
+		log.Info("Would clean up deleted documents", "count", len(deletedDocuments))
+	} else {
+		log.Info("Cleaned up deleted documents", "count", len(deletedDocuments))
+	}
+
+	var syncErr error
+	if c.layout != nil {
+		syncErr = c.renameFiles(ctx, db, documents)
+	}
+	if syncErr == nil {
+		syncErr = c.downloadEach(ctx, clt, db, pendingDocuments)
+	}
+	if c.DryRun {
+		// the DB has been updated in memory only
+		return syncErr
+	}
+	c.purgeTrash(ctx)
+	log.V(1).Info("Saving DB")
+	if closeErr := db.Close(); closeErr != nil {
+		return closeErr
+	}
+	if syncErr == nil && c.ExportFormat != "" {
+		syncErr = c.writeExport(ctx, db, manifest)
+	}
+	return syncErr
+}
+
+// writeExport writes the manifest with all documents in the DB into the target dir.
+func (c *BulkDownloadCommand) writeExport(ctx *cli.Context, db *localdb.Database, manifest *export.Manifest) error {
+	manifest.ExportedAt = time.Now()
+	manifest.Server = c.PaperlessURL
+	manifest.Documents = make([]export.Document, 0)
+	for _, doc := range db.GetAll() {
+		manifest.Documents = append(manifest.Documents, export.Document{Document: doc, Files: db.GetFiles(doc.ID)})
+	}
+	file, err := export.WriteFile(c.getTargetPath(), manifest, export.Format(c.ExportFormat))
+	if err != nil {
+		return err
+	}
+	logr.FromContextOrDiscard(ctx.Context).Info("Exported manifest", "file", file, "documents", len(manifest.Documents))
+	return nil
+}
+
+// checkDeletions returns an error if the number of deleted documents exceeds the threshold.
+// An empty or incomplete listing from the server, e.g. due to missing permissions, would otherwise wipe the mirror.
+func (c *BulkDownloadCommand) checkDeletions(ctx *cli.Context, deleted, total int) error {
+	threshold, err := safedelete.ParseThreshold(c.MaxDeletions)
+	if err != nil {
+		return err
+	}
+	if !threshold.Exceeded(deleted, total) {
+		return nil
+	}
+	if c.Force {
+		logr.FromContextOrDiscard(ctx.Context).Info("Threshold of deletions exceeded, but forced to continue",
+			"count", deleted, "total", total)
+		return nil
+	}
+	return fmt.Errorf("refusing to delete %d of %d local documents as it exceeds --%s=%s, use --%s to delete them anyway",
+		deleted, total, newMaxDeletionsFlag(nil).Name, threshold, newForceFlag(nil).Name)
+}
+
+// discardFile moves the file at the given relative path to the trash and removes empty parent directories.
+func (c *BulkDownloadCommand) discardFile(ctx *cli.Context, relPath, reason string) {
+	if err := c.trash.Discard(relPath, reason); err != nil {
+		logr.FromContextOrDiscard(ctx.Context).Error(err, "Could not remove file", "path", relPath)
+		return
+	}
+	removeEmptyDirs(c.getTargetPath(), relPath)
+}
+
+// purgeTrash deletes the files in the trash whose retention has expired.
+func (c *BulkDownloadCommand) purgeTrash(ctx *cli.Context) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	purged, err := c.trash.Purge()
+	if err != nil {
+		log.Error(err, "Could not purge trash", "dir", c.trash.Dir())
+	}
+	for _, dir := range purged {
+		log.V(1).Info("Purged expired trash", "dir", dir)
+	}
+}
+
+// isOutdated returns true if the local files of the document have to be downloaded (again).
+// Without layout, the file names are given by Paperless, so any change on the server could mean a renamed file.
+func (c *BulkDownloadCommand) isOutdated(db *localdb.Database, localDoc, serverDoc paperless.Document) bool {
+	if len(db.GetFiles(localDoc.ID)) == 0 {
+		// downloaded by a previous version or with a different layout, location is unknown
+		return true
+	}
+	return c.layout == nil && localDoc.Modified != serverDoc.Modified
+}
+
+// toLocalDocument returns the document as it should be stored in the DB.
+func (c *BulkDownloadCommand) toLocalDocument(doc paperless.Document) paperless.Document {
+	if !c.WithContent {
+		doc.Content = ""
+	}
+	return doc
+}
+
+// renameFiles moves the files of existing documents if their rendered path has changed, e.g. due to a new title.
+func (c *BulkDownloadCommand) renameFiles(ctx *cli.Context, db *localdb.Database, documents []paperless.Document) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	dir := c.getTargetPath()
+
+	renamed := 0
+	for _, doc := range documents {
+		oldPaths := db.GetFiles(doc.ID)
+		newPaths := make([]string, len(oldPaths))
+		for i, oldPath := range oldPaths {
+			variantDir, _, _ := strings.Cut(oldPath, "/")
+			newPath := path.Join(variantDir, c.layout.Render(doc, db, path.Ext(oldPath)))
+			newPath = db.UniquePath(doc.ID, newPath)
+			newPaths[i] = newPath
+			if newPath == oldPath {
+				continue
+			}
+			renamed++
+			c.result.add(doc, downloadActionRenamed, newPath)
+			if c.DryRun {
+				log.Info("Would rename document", "id", doc.ID, "from", oldPath, "to", newPath)
+			} else {
+				log.V(1).Info("Renaming document", "id", doc.ID, "from", oldPath, "to", newPath)
+				if err := moveFile(filepath.Join(dir, filepath.FromSlash(oldPath)), filepath.Join(dir, filepath.FromSlash(newPath))); err != nil {
+					return fmt.Errorf("cannot rename document %d: %w", doc.ID, err)
+				}
+				removeEmptyDirs(dir, oldPath)
+			}
+			// update immediately to detect collisions with the next document
+			db.SetFiles(doc.ID, append(newPaths[:i+1:i+1], oldPaths[i+1:]...))
+		}
+	}
+	if c.DryRun {
+		log.Info("Would rename changed documents", "count", renamed)
+		return nil
+	}
+	log.Info("Renamed changed documents", "count", renamed)
+	return nil
+}
+
+// downloadEach downloads the given documents one by one and records the paths of their files.
+// Files of a previous download that don't exist anymore in the new download are removed.
+func (c *BulkDownloadCommand) downloadEach(ctx *cli.Context, clt *paperless.Client, db *localdb.Database, documents []paperless.Document) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	if len(documents) == 0 {
+		log.Info("Nothing to download")
+		return nil
+	}
+
+	for _, doc := range documents {
+		if db.FindByID(doc.ID) == nil {
+			c.result.add(doc, downloadActionDownloaded)
+		} else {
+			c.result.add(doc, downloadActionUpdated)
+		}
+	}
+	if c.DryRun {
+		for _, doc := range documents {
+			if db.FindByID(doc.ID) == nil {
+				log.Info("Would download new document", "id", doc.ID, "title", doc.Title)
+			} else {
+				log.Info("Would update changed document", "id", doc.ID, "title", doc.Title, "files", db.GetFiles(doc.ID))
+			}
+		}
+		log.Info("Would download documents", "count", len(documents))
+		return nil
+	}
+
+	log.Info("Downloading documents", "count", len(documents))
+	for _, doc := range documents {
+		var paths []string
+		var err error
+		if c.layout != nil {
+			paths, err = c.downloadWithLayout(ctx, clt, db, doc)
+		} else {
+			paths, err = c.downloadArchive(ctx, clt, db, doc)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot download document %d: %w", doc.ID, err)
+		}
+		db.Put(c.toLocalDocument(doc))
+		for _, oldPath := range db.ReplaceFiles(doc.ID, paths) {
+			log.V(1).Info("Removing outdated file", "id", doc.ID, "path", oldPath)
+			c.discardFile(ctx, oldPath, fmt.Sprintf("document %d changed on server", doc.ID))
+		}
+		c.result.setFiles(doc.ID, paths)
+	}
+	log.Info("Downloaded documents to dir", "dir", c.getTargetPath())
+	return nil
+}
+
+// downloadArchive downloads the document as zip archive and unzips it, keeping the file names given by Paperless.
+// The files are extracted into a temporary dir first, so that a file name that belongs to another document
+// gets a numbered suffix instead of overwriting the file of the other document.
+func (c *BulkDownloadCommand) downloadArchive(ctx *cli.Context, clt *paperless.Client, db *localdb.Database, doc paperless.Document) ([]string, error) {
+	tmpFile, err := c.downloadDocuments(ctx, clt, []int{doc.ID})
+	if tmpFile != nil {
+		defer os.Remove(tmpFile.Name())
+		defer tmpFile.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir := c.getTargetPath()
+	if mkdirErr := os.MkdirAll(dir, os.ModePerm); mkdirErr != nil {
+		return nil, fmt.Errorf("cannot create directory: %w", mkdirErr)
+	}
+	tmpDir, createTempErr := os.MkdirTemp(dir, ".paperless-unzip-")
+	if createTempErr != nil {
+		return nil, fmt.Errorf("cannot create temporary directory: %w", createTempErr)
+	}
+	defer os.RemoveAll(tmpDir)
+	files, unzipErr := archive.Unzip(ctx.Context, tmpFile.Name(), tmpDir)
+	if unzipErr != nil {
+		return nil, fmt.Errorf("cannot unzip file %q: %w", tmpFile.Name(), unzipErr)
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		relPath := db.UniquePath(doc.ID, path.Join(c.unzipSubDir(), file))
+		if moveErr := moveFile(filepath.Join(tmpDir, filepath.FromSlash(file)), filepath.Join(dir, filepath.FromSlash(relPath))); moveErr != nil {
+			for _, p := range paths {
+				if _, owned := db.FindByFile(p); !owned {
+					removeFile(dir, p)
+				}
+			}
+			return nil, moveErr
+		}
+		paths = append(paths, relPath)
+	}
+	return paths, nil
+}
+
+// downloadWithLayout downloads each variant of the document and places the files according to the layout.
+func (c *BulkDownloadCommand) downloadWithLayout(ctx *cli.Context, clt *paperless.Client, db *localdb.Database, doc paperless.Document) ([]string, error) {
+	paths := make([]string, 0, 2)
+	for _, v := range c.getVariants(doc) {
+		filePath, err := c.downloadVariant(ctx, clt, db, doc, v)
+		if err != nil {
+			for _, p := range paths {
+				if _, owned := db.FindByFile(p); !owned {
+					removeFile(c.getTargetPath(), p)
+				}
+			}
+			return nil, err
+		}
+		paths = append(paths, filePath)
+	}
+	return paths, nil
+}
+
+// downloadVariant downloads a single file of the document and returns its path relative to the target dir.
+func (c *BulkDownloadCommand) downloadVariant(ctx *cli.Context, clt *paperless.Client, db *localdb.Database, doc paperless.Document, v variant) (string, error) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	dir := c.getTargetPath()
+
+	ext := path.Ext(doc.OriginalFileName)
+	if !v.original && doc.ArchivedFileName != "" {
+		ext = path.Ext(doc.ArchivedFileName)
+	}
+	relPath := path.Join(v.dir, c.layout.Render(doc, db, ext))
+	relPath = db.UniquePath(doc.ID, relPath)
+
+	if mkdirErr := os.MkdirAll(dir, os.ModePerm); mkdirErr != nil {
+		return "", fmt.Errorf("cannot create directory: %w", mkdirErr)
+	}
+	tmpFile, createTempErr := os.CreateTemp(dir, ".paperless-download-")
+	if createTempErr != nil {
+		return "", fmt.Errorf("cannot open temporary file: %w", createTempErr)
+	}
+	defer os.Remove(tmpFile.Name()) // cleanup if not renamed
+
+	log.V(1).Info("Downloading document", "id", doc.ID, "path", relPath)
+	downloadErr := clt.DownloadDocument(ctx.Context, tmpFile, doc.ID, v.original)
+	closeErr := tmpFile.Close()
+	if downloadErr != nil {
+		return "", downloadErr
+	}
+	if closeErr != nil {
+		return "", fmt.Errorf("cannot write temporary file: %w", closeErr)
+	}
+	return relPath, moveFile(tmpFile.Name(), filepath.Join(dir, filepath.FromSlash(relPath)))
+}
+
+// getVariants returns the variants to download according to the selected content.
+func (c *BulkDownloadCommand) getVariants(doc paperless.Document) []variant {
+	switch paperless.BulkDownloadContent(c.Content) {
+	case paperless.BulkDownloadOriginal:
+		return []variant{originalVariant}
+	case paperless.BulkDownloadBoth:
+		if doc.ArchivedFileName == "" {
+			return []variant{originalVariant}
+		}
+		return []variant{archiveVariant, originalVariant}
+	default:
+		// Paperless falls back to the original if there's no archived version.
+		return []variant{archiveVariant}
+	}
+}
+
+// moveFile renames the source file to the destination, creating parent directories if needed.
+func moveFile(source, dest string) error {
+	if mkdirErr := os.MkdirAll(filepath.Dir(dest), os.ModePerm); mkdirErr != nil {
+		return fmt.Errorf("cannot create directory: %w", mkdirErr)
+	}
+	if renameErr := os.Rename(source, dest); renameErr != nil {
+		return fmt.Errorf("cannot move file: %w", renameErr)
+	}
+	return nil
+}
+
+// removeFile removes the file at the given relative path and its empty parent directories.
+func removeFile(baseDir, relPath string) {
+	_ = os.Remove(filepath.Join(baseDir, filepath.FromSlash(relPath)))
+	removeEmptyDirs(baseDir, relPath)
+}
+
+// removeEmptyDirs removes the parent directories of the given relative file path, as long as they are empty.
+// The base dir itself is never removed.
+func removeEmptyDirs(baseDir, relPath string) {
+	for parent := path.Dir(relPath); parent != "." && parent != "/"; parent = path.Dir(parent) {
+		if err := os.Remove(filepath.Join(baseDir, filepath.FromSlash(parent))); err != nil {
+			// not empty or not existing
+			return
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+type BulkEditCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	DocumentIDs        cli.IntSlice
+	Query              string
+	AddTags            cli.StringSlice
+	RemoveTags         cli.StringSlice
+	Correspondent      string
+	DocumentType       string
+	StoragePath        string
+	SetCustomFields    cli.StringSlice
+	RemoveCustomFields cli.StringSlice
+	Rotate             int
+	Merge              bool
+	Reprocess          bool
+	Delete             bool
+	Yes                bool
+	DryRun             bool
+}
+
+const bulkEditDesc = `Select the documents with --%s and/or --%s and apply one or more operations to all of them.
+Tags, correspondents, document types, storage paths and custom fields can be given by name or ID.
+The operations are applied in the order of the flags as listed below.`
+
+func newBulkEditCommand() *BulkEditCommand {
+	c := &BulkEditCommand{}
+	c.Command = cli.Command{
+		Name:        "bulk-edit",
+		Usage:       "Edits multiple documents at once",
+		Description: fmt.Sprintf(bulkEditDesc, newDocumentIDFlag(nil).Name, newQueryFlag(nil).Name),
+		Before:      loadConfigFileFn,
+		Action:      actions(LogMetadata, c.Action),
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newDocumentIDFlag(&c.DocumentIDs),
+			newQueryFlag(&c.Query),
+			newAddTagFlag(&c.AddTags),
+			newRemoveTagFlag(&c.RemoveTags),
+			newSetCorrespondentFlag(&c.Correspondent),
+			newSetDocumentTypeFlag(&c.DocumentType),
+			newSetStoragePathFlag(&c.StoragePath),
+			newSetCustomFieldFlag(&c.SetCustomFields),
+			newRemoveCustomFieldFlag(&c.RemoveCustomFields),
+			newRotateFlag(&c.Rotate),
+			newMergeFlag(&c.Merge),
+			newReprocessFlag(&c.Reprocess),
+			newDeleteFlag(&c.Delete),
+			newYesFlag(&c.Yes),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *BulkEditCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	operations, err := c.getOperations(ctx, paperless.NewResolver(clt))
+	if err != nil {
+		return err
+	}
+	if len(operations) == 0 {
+		return showFlagError(ctx, fmt.Errorf("at least one operation is required"))
+	}
+
+	documentIDs, err := selectDocuments(ctx, clt, c.DocumentIDs.Value(), c.Query)
+	if err != nil {
+		return err
+	}
+	if len(documentIDs) == 0 {
+		log.Info("No documents selected")
+		return printDocumentResults(ctx, []documentResult{})
+	}
+	log.Info("Selected documents", "count", len(documentIDs))
+
+	if c.Delete && !c.DryRun {
+		confirmed, confirmErr := confirm(fmt.Sprintf("Delete %d document(s)?", len(documentIDs)), c.Yes)
+		if confirmErr != nil {
+			return confirmErr
+		}
+		if !confirmed {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	for _, op := range operations {
+		op.DocumentIDs = documentIDs
+		if c.DryRun {
+			log.Info("Would apply operation", "method", op.Method, "parameters", op.Parameters, "document_ids", documentIDs)
+			continue
+		}
+		log.V(1).Info("Applying operation", "method", op.Method, "parameters", op.Parameters)
+		if editErr := clt.BulkEdit(ctx.Context, op); editErr != nil {
+			return errors.Wrap(editErr, "cannot apply %s", op.Method)
+		}
+		log.Info("Applied operation", "method", op.Method, "count", len(documentIDs))
+	}
+	methods := make([]string, len(operations))
+	for i, op := range operations {
+		methods[i] = string(op.Method)
+	}
+	results := make([]documentResult, len(documentIDs))
+	for i, id := range documentIDs {
+		results[i] = documentResult{ID: id, Action: strings.Join(methods, ",")}
+	}
+	return printDocumentResults(ctx, results)
+}
+
+// getOperations returns the bulk edit operations given by the flags, without the document IDs.
+func (c *BulkEditCommand) getOperations(ctx *cli.Context, resolver *paperless.Resolver) ([]paperless.BulkEditParams, error) {
+	ops := make([]paperless.BulkEditParams, 0)
+	if len(c.AddTags.Value()) > 0 || len(c.RemoveTags.Value()) > 0 {
+		addIDs, err := resolver.TagIDs(ctx.Context, c.AddTags.Value())
+		if err != nil {
+			return nil, err
+		}
+		removeIDs, err := resolver.TagIDs(ctx.Context, c.RemoveTags.Value())
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditModifyTags, Parameters: paperless.ModifyTagsParams(addIDs, removeIDs)})
+	}
+	if ctx.IsSet(newSetCorrespondentFlag(nil).Name) {
+		id, err := resolveOptionalID(ctx, resolver.CorrespondentID, c.Correspondent)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditSetCorrespondent, Parameters: paperless.SetCorrespondentParams(id)})
+	}
+	if ctx.IsSet(newSetDocumentTypeFlag(nil).Name) {
+		id, err := resolveOptionalID(ctx, resolver.DocumentTypeID, c.DocumentType)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditSetDocumentType, Parameters: paperless.SetDocumentTypeParams(id)})
+	}
+	if ctx.IsSet(newSetStoragePathFlag(nil).Name) {
+		id, err := resolveOptionalID(ctx, resolver.StoragePathID, c.StoragePath)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditSetStoragePath, Parameters: paperless.SetStoragePathParams(id)})
+	}
+	if len(c.SetCustomFields.Value()) > 0 || len(c.RemoveCustomFields.Value()) > 0 {
+		fields, err := resolveCustomFieldValues(ctx, resolver, c.SetCustomFields.Value())
+		if err != nil {
+			return nil, err
+		}
+		removeIDs := make([]int, 0)
+		for _, name := range c.RemoveCustomFields.Value() {
+			id, resolveErr := resolver.CustomFieldID(ctx.Context, name)
+			if resolveErr != nil {
+				return nil, resolveErr
+			}
+			removeIDs = append(removeIDs, id)
+		}
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditModifyCustomFields, Parameters: paperless.ModifyCustomFieldsParams(fields, removeIDs)})
+	}
+	if c.Rotate != 0 {
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditRotate, Parameters: paperless.RotateParams(c.Rotate)})
+	}
+	if c.Merge {
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditMerge})
+	}
+	if c.Reprocess {
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditReprocess})
+	}
+	if c.Delete {
+		ops = append(ops, paperless.BulkEditParams{Method: paperless.BulkEditDelete})
+	}
+	return ops, nil
+}
+
+// selectDocuments returns the given document IDs and the IDs of all documents matching the full text query, if not empty.
+func selectDocuments(ctx *cli.Context, clt *paperless.Client, ids []int, query string) ([]int, error) {
+	if len(ids) == 0 && query == "" {
+		return nil, showFlagError(ctx, fmt.Errorf("select documents with --%s or --%s", newDocumentIDFlag(nil).Name, newQueryFlag(nil).Name))
+	}
+	selected := make([]int, 0, len(ids))
+	seen := map[int]bool{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, id)
+		}
+	}
+	if query == "" {
+		return selected, nil
+	}
+	documents, err := clt.QueryDocuments(ctx.Context, paperless.QueryParams{
+		TruncateContent: true,
+		PageSize:        100,
+		Query:           query,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query documents")
+	}
+	for _, id := range paperless.MapToDocumentIDs(documents) {
+		if !seen[id] {
+			seen[id] = true
+			selected = append(selected, id)
+		}
+	}
+	return selected, nil
+}
+
+// resolveOptionalID returns nil if nameOrID is empty, otherwise the resolved ID.
+func resolveOptionalID(ctx *cli.Context, resolve func(ctx context.Context, nameOrID string) (int, error), nameOrID string) (*int, error) {
+	if nameOrID == "" {
+		return nil, nil
+	}
+	id, err := resolve(ctx.Context, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// resolveCustomFieldValues parses the given "NAME=VALUE" pairs and returns the values by custom field ID.
+func resolveCustomFieldValues(ctx *cli.Context, resolver *paperless.Resolver, pairs []string) (map[int]any, error) {
+	fields := map[int]any{}
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid custom field %q: must be in the form NAME=VALUE", pair)
+		}
+		id, err := resolver.CustomFieldID(ctx.Context, name)
+		if err != nil {
+			return nil, err
+		}
+		fields[id] = value
+	}
+	return fields, nil
+}
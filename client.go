@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ccremer/paperless-cli/pkg/oidc"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/transport"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/oauth2"
+)
+
+// clientFactory creates paperless clients that use the HTTP transport configured by the global flags.
+// If bearer token authentication is configured, the clients of a factory share the token source,
+// so that the OIDC flow runs at most once for them.
+type clientFactory struct {
+	bearerTokenSource oauth2.TokenSource
+}
+
+// newClient returns a paperless client with its own bearer token source, if configured.
+func newClient(ctx *cli.Context, url, username, passwordOrToken string) (*paperless.Client, error) {
+	return (&clientFactory{}).newClient(ctx, url, username, passwordOrToken)
+}
+
+// newClient returns a paperless client that uses the HTTP transport configured by the global flags.
+// If bearer token authentication is configured, the transport sets the bearer token instead of the given credentials.
+func (f *clientFactory) newClient(ctx *cli.Context, url, username, passwordOrToken string) (*paperless.Client, error) {
+	opts, err := transportOptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	httpClient, err := transport.NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	source, err := f.getBearerTokenSource(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if source != nil {
+		username, passwordOrToken = "", ""
+		httpClient = &http.Client{
+			Transport: &oauth2.Transport{Source: source, Base: httpClient.Transport},
+			Timeout:   httpClient.Timeout,
+		}
+	}
+	clt := paperless.NewClient(url, username, passwordOrToken)
+	clt.HttpClient = httpClient
+	return clt, nil
+}
+
+func transportOptions(ctx *cli.Context) (transport.Options, error) {
+	headers, err := transport.ParseHeaders(ctx.StringSlice(newHeaderFlag().Name))
+	if err != nil {
+		return transport.Options{}, err
+	}
+	return transport.Options{
+		TLS: transport.TLSOptions{
+			CAFile:             ctx.String(newCAFileFlag().Name),
+			CertFile:           ctx.String(newClientCertFlag().Name),
+			KeyFile:            ctx.String(newClientKeyFlag().Name),
+			MinVersion:         ctx.String(newTLSMinVersionFlag().Name),
+			InsecureSkipVerify: ctx.Bool(newInsecureSkipVerifyFlag().Name),
+		},
+		Proxy:          ctx.String(newProxyFlag().Name),
+		ConnectTimeout: ctx.Duration(newConnectTimeoutFlag().Name),
+		RequestTimeout: ctx.Duration(newRequestTimeoutFlag().Name),
+		ReadTimeout:    ctx.Duration(newReadTimeoutFlag().Name),
+		IdleTimeout:    ctx.Duration(newIdleTimeoutFlag().Name),
+		Headers:        headers,
+	}, nil
+}
+
+// getBearerTokenSource returns the source of bearer tokens configured by the global flags.
+// It returns nil if bearer token authentication isn't configured.
+// The OIDC issuer is another party than Paperless, so it doesn't get the additional headers and the client certificate.
+func (f *clientFactory) getBearerTokenSource(ctx *cli.Context, opts transport.Options) (oauth2.TokenSource, error) {
+	if f.bearerTokenSource != nil {
+		return f.bearerTokenSource, nil
+	}
+	bearerToken, issuer := ctx.String(newBearerTokenFlag().Name), ctx.String(newOIDCIssuerFlag().Name)
+	switch {
+	case bearerToken != "" && issuer != "":
+		return nil, fmt.Errorf("flags --%s and --%s cannot be combined", newBearerTokenFlag().Name, newOIDCIssuerFlag().Name)
+	case bearerToken != "":
+		f.bearerTokenSource = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: bearerToken, TokenType: "Bearer"})
+	case issuer != "":
+		flow, err := oidc.ParseFlow(ctx.String(newOIDCFlowFlag().Name))
+		if err != nil {
+			return nil, err
+		}
+		issuerClient, err := transport.NewClient(opts.Anonymous())
+		if err != nil {
+			return nil, err
+		}
+		log := logr.FromContextOrDiscard(ctx.Context)
+		log.V(1).Info("Obtaining OIDC token", "issuer", issuer, "flow", flow)
+		source, err := oidc.NewTokenSource(ctx.Context, issuerClient, oidc.Config{
+			Issuer:       issuer,
+			ClientID:     ctx.String(newOIDCClientIDFlag().Name),
+			ClientSecret: ctx.String(newOIDCClientSecretFlag().Name),
+			Scopes:       ctx.StringSlice(newOIDCScopeFlag().Name),
+			Flow:         flow,
+		}, func(verificationURI, userCode string) {
+			log.Info("To continue, open the URL in a browser and enter the code", "url", verificationURI, "code", userCode)
+		})
+		if err != nil {
+			return nil, err
+		}
+		f.bearerTokenSource = source
+	}
+	return f.bearerTokenSource, nil
+}
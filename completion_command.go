@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/completion"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// completionTimeout limits how long the completion waits for the server, so that the shell doesn't hang.
+	completionTimeout = 5 * time.Second
+	// completionCacheTTL is how long the names fetched from the server are used for completion.
+	completionCacheTTL = 10 * time.Minute
+)
+
+// completionScripts are the templates of the completion scripts by shell.
+// They run the CLI with "--generate-bash-completion" as last argument, which prints the suggestions one per line.
+var completionScripts = map[string]string{
+	"bash": `# bash completion for {{.Name}}
+_{{.Func}}_completion() {
+  local cur prev words cword requestComp opts
+  if declare -F _init_completion >/dev/null 2>&1; then
+    _init_completion -n "=:" || return
+  else
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    words=("${COMP_WORDS[@]}")
+    cword=$COMP_CWORD
+  fi
+  words=("${words[@]:0:$cword}")
+  if [[ "$cur" == "-"* ]]; then
+    requestComp="${words[*]} ${cur} --generate-bash-completion"
+  else
+    requestComp="${words[*]} --generate-bash-completion"
+  fi
+  opts=$(eval "${requestComp}" 2>/dev/null)
+  local IFS=$'\n'
+  COMPREPLY=($(compgen -W "${opts}" -- "${cur}"))
+  COMPREPLY=("${COMPREPLY[@]// /\\ }")
+  return 0
+}
+
+complete -o bashdefault -o default -F _{{.Func}}_completion {{.Name}}
+`,
+	"zsh": `#compdef {{.Name}}
+
+_{{.Func}}_completion() {
+  local -a opts
+  local cur
+  cur=${words[-1]}
+  if [[ "$cur" == "-"* ]]; then
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} ${cur} --generate-bash-completion 2>/dev/null)}")
+  else
+    opts=("${(@f)$(${words[@]:0:#words[@]-1} --generate-bash-completion 2>/dev/null)}")
+  fi
+
+  if [[ "${opts[1]}" != "" ]]; then
+    _describe 'values' opts
+  else
+    _files
+  fi
+}
+
+compdef _{{.Func}}_completion {{.Name}}
+`,
+	"fish": `# fish completion for {{.Name}}
+function __{{.Func}}_completion
+    set -l args (commandline -opc)
+    set -l cur (commandline -ct)
+    if string match -q -- '-*' $cur
+        set -a args $cur
+    end
+    set -l opts ($args --generate-bash-completion 2>/dev/null)
+    if test (count $opts) -eq 0
+        __fish_complete_path $cur
+        return
+    end
+    printf '%s\n' $opts
+end
+
+complete -c {{.Name}} -f -a '(__{{.Func}}_completion)'
+`,
+	"powershell": `# powershell completion for {{.Name}}
+Register-ArgumentCompleter -Native -CommandName '{{.Name}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+    $words = @($commandAst.CommandElements | Where-Object { $_.Extent.EndOffset -le $cursorPosition } | ForEach-Object { $_.ToString() })
+    if ($wordToComplete -ne '' -and -not $wordToComplete.StartsWith('-')) {
+        $words = @($words | Select-Object -SkipLast 1)
+    }
+    $program, $arguments = $words
+    & $program @arguments --generate-bash-completion 2>$null | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        $completion = $_
+        if ($completion -match '\s') {
+            $completion = "'" + ($completion -replace "'", "''") + "'"
+        }
+        [System.Management.Automation.CompletionResult]::new($completion, $_, 'ParameterValue', $_)
+    }
+}
+`,
+}
+
+// completionShells are the supported shells in the order they are documented.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// entityFlagKinds maps the flags that expect names of entities to the kind of entity.
+var entityFlagKinds = map[string]entityKind{
+	newTagFlag(nil).Name:              tagKind,
+	newAddTagFlag(nil).Name:           tagKind,
+	newRemoveTagFlag(nil).Name:        tagKind,
+	newSetTagsFlag(nil).Name:          tagKind,
+	newCorrespondentFlag(nil).Name:    correspondentKind,
+	newSetCorrespondentFlag(nil).Name: correspondentKind,
+	newDocumentTypeFlag(nil).Name:     documentTypeKind,
+	newSetDocumentTypeFlag(nil).Name:  documentTypeKind,
+	newSetStoragePathFlag(nil).Name:   storagePathKind,
+}
+
+// profileFlags are the flags that expect the name of a profile in the config file.
+var profileFlags = []string{newProfileFlag().Name, newSourceProfileFlag(nil).Name, newTargetProfileFlag(nil).Name}
+
+type CompletionCommand struct {
+	cli.Command
+}
+
+func newCompletionCommand() *CompletionCommand {
+	c := &CompletionCommand{}
+	c.Command = cli.Command{
+		Name:  "completion",
+		Usage: "Prints the shell completion script",
+		Description: fmt.Sprintf(`Supported shells: %s.
+To load the completion in the current bash session, run:
+  source <(%s completion bash)`, strings.Join(completionShells, ", "), appName),
+		Before:    requireArgs,
+		Action:    c.Action,
+		ArgsUsage: strings.Join(completionShells, "|"),
+		BashComplete: func(ctx *cli.Context) {
+			if ctx.NArg() > 0 {
+				return
+			}
+			for _, shell := range completionShells {
+				fmt.Fprintln(ctx.App.Writer, shell)
+			}
+		},
+	}
+	return c
+}
+
+func (c *CompletionCommand) Action(ctx *cli.Context) error {
+	shell := ctx.Args().First()
+	script, found := completionScripts[shell]
+	if !found {
+		return fmt.Errorf("unsupported shell %q, supported: %v", shell, completionShells)
+	}
+	tmpl, err := template.New(shell).Parse(script)
+	if err != nil {
+		return fmt.Errorf("cannot parse completion script: %w", err)
+	}
+	return tmpl.Execute(resultWriter, map[string]string{
+		"Name": appName,
+		// function names in shell scripts may not contain dashes in all shells
+		"Func": strings.ReplaceAll(appName, "-", "_"),
+	})
+}
+
+// enableCompletion sets completeCommand as completion of the given commands and their subcommands,
+// unless they already have their own completion.
+func enableCompletion(commands []*cli.Command) {
+	for _, cmd := range commands {
+		if cmd.BashComplete == nil {
+			cmd.BashComplete = completeCommand
+		}
+		enableCompletion(cmd.Subcommands)
+	}
+}
+
+// completeCommand prints the names of entities or profiles if the previous argument is a flag expecting them.
+// Otherwise, it prints the flags or subcommands of the current command.
+func completeCommand(ctx *cli.Context) {
+	flagName := previousFlag()
+	if slices.Contains(profileFlags, flagName) {
+		printCompletions(ctx, profileNames(ctx))
+		return
+	}
+	if kind, found := entityFlagKinds[flagName]; found {
+		printCompletions(ctx, entityNames(ctx, kind))
+		return
+	}
+	cli.DefaultCompleteWithFlags(ctx.Command)(ctx)
+}
+
+// completeEntityNames returns a completion that prints the names of entities of the given kind as arguments.
+func completeEntityNames(kind entityKind) cli.BashCompleteFunc {
+	return func(ctx *cli.Context) {
+		if previousFlag() != "" {
+			completeCommand(ctx)
+			return
+		}
+		printCompletions(ctx, entityNames(ctx, kind))
+	}
+}
+
+// previousFlag returns the name of the flag that is the last argument before the completed one, or empty string.
+// The last argument of os.Args is "--generate-bash-completion".
+func previousFlag() string {
+	if len(os.Args) < 3 {
+		return ""
+	}
+	arg := os.Args[len(os.Args)-2]
+	if !strings.HasPrefix(arg, "-") {
+		return ""
+	}
+	return strings.TrimLeft(arg, "-")
+}
+
+func printCompletions(ctx *cli.Context, values []string) {
+	for _, value := range values {
+		fmt.Fprintln(ctx.App.Writer, value)
+	}
+}
+
+// profileNames returns the names of the profiles in the config file.
+// Errors are ignored, as there's no way to show them while completing.
+func profileNames(ctx *cli.Context) []string {
+	values, err := readConfigFile(ctx.String(newConfigFileFlag().Name))
+	if err != nil {
+		return nil
+	}
+	return sortedProfileNames(getProfiles(values))
+}
+
+// entityNames returns the names of the entities of the given kind from the server.
+// The names are cached, so that the server isn't queried on each completion.
+// Errors are ignored, as there's no way to show them while completing.
+func entityNames(ctx *cli.Context, kind entityKind) []string {
+	// the Before functions aren't run when completing, so the config file has to be applied here.
+	lineage := ctx.Lineage()
+	for i := len(lineage) - 1; i >= 0; i-- {
+		if lineage[i].Command == nil {
+			continue
+		}
+		if err := ignoreMissingProfile(loadConfigFileFn)(lineage[i]); err != nil {
+			return nil
+		}
+	}
+	url := ctx.String(newURLFlag(nil).Name)
+	if url == "" {
+		return nil
+	}
+
+	fetch := func() ([]string, error) {
+		clt, err := newClient(ctx, url, ctx.String(newUsernameFlag(nil).Name), ctx.String(newTokenFlag(nil).Name))
+		if err != nil {
+			return nil, err
+		}
+		queryCtx, cancel := context.WithTimeout(ctx.Context, completionTimeout)
+		defer cancel()
+		rows, err := kind.list(queryCtx, clt)
+		if err != nil {
+			return nil, err
+		}
+		names := make([]string, len(rows))
+		for i, row := range rows {
+			names[i] = row.Name
+		}
+		return names, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		names, _ := fetch()
+		return names
+	}
+	cache := completion.NewCache(filepath.Join(dir, appName), completionCacheTTL)
+	names, _ := cache.Get(url+"#"+kind.name, fetch)
+	return names
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// profilesKey is the key in the config file that contains the named profiles.
+	profilesKey = "profiles"
+	// defaultProfileKey is the key in the config file that contains the name of the profile to use if none is given.
+	defaultProfileKey = "profile"
+)
+
+// errProfileNotFound is returned if the selected profile doesn't exist in the config file.
+var errProfileNotFound = errors.New("not found in config file")
+
+// loadConfigFileFn applies the values of the config file to the flags of the current command.
+// The values of the selected profile take precedence over the top-level values of the config file.
+func loadConfigFileFn(ctx *cli.Context) error {
+	return loadConfigFile(ctx, nil)
+}
+
+// ignoreMissingProfile returns a cli.BeforeFunc that ignores if the selected profile doesn't exist in the config file.
+// The config file isn't applied in that case.
+func ignoreMissingProfile(fn cli.BeforeFunc) cli.BeforeFunc {
+	return func(ctx *cli.Context) error {
+		if err := fn(ctx); err != nil && !errors.Is(err, errProfileNotFound) {
+			return err
+		}
+		return nil
+	}
+}
+
+// loadConfigFile applies the values of the config file to the flags of the current command.
+// In addition to the global profile, prefixedProfiles selects further profiles by flag name,
+// whose values are applied with the prefix as key, e.g. "url" of the profile given by "--source-profile" as "source-url".
+func loadConfigFile(ctx *cli.Context, prefixedProfiles map[string]string) error {
+	path := ctx.String(newConfigFileFlag().Name)
+	values, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	profile := ctx.String(newProfileFlag().Name)
+	if profile == "" {
+		profile, _ = values[defaultProfileKey].(string)
+	}
+	if profile != "" {
+		if err := applyProfile(values, profile, ""); err != nil {
+			return err
+		}
+	}
+	for prefix, flagName := range prefixedProfiles {
+		if name := ctx.String(flagName); name != "" {
+			if err := applyProfile(values, name, prefix); err != nil {
+				return err
+			}
+		}
+	}
+	if len(values) > 0 {
+		if err := altsrc.ApplyInputSourceValues(ctx, altsrc.NewMapInputSource(path, values), ctx.Command.Flags); err != nil {
+			return err
+		}
+	}
+	prefixes := []string{""}
+	for prefix := range prefixedProfiles {
+		prefixes = append(prefixes, prefix)
+	}
+	return resolveTokens(ctx, values, prefixes)
+}
+
+// readConfigFile returns the values of the given YAML file.
+// It returns an empty map if the file doesn't exist.
+func readConfigFile(path string) (map[any]any, error) {
+	values := map[any]any{}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("cannot read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// applyProfile copies the values of the named profile to the top-level values, prefixing their keys.
+func applyProfile(values map[any]any, name, prefix string) error {
+	profiles := getProfiles(values)
+	profile, found := profiles[name]
+	if !found {
+		return fmt.Errorf("profile %q %w, available profiles: [%s]", name, errProfileNotFound, strings.Join(sortedProfileNames(profiles), ", "))
+	}
+	for key, value := range profile {
+		values[prefix+key] = value
+	}
+	return nil
+}
+
+// getProfiles returns the profiles of the config file by name.
+func getProfiles(values map[any]any) map[string]map[string]any {
+	profiles := map[string]map[string]any{}
+	for name, profile := range toStringMap(values[profilesKey]) {
+		profiles[name] = toStringMap(profile)
+	}
+	return profiles
+}
+
+func sortedProfileNames(profiles map[string]map[string]any) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// toStringMap converts a YAML mapping to a map with string keys.
+// It returns an empty map if value isn't a mapping.
+func toStringMap(value any) map[string]any {
+	result := map[string]any{}
+	switch m := value.(type) {
+	case map[string]any:
+		for k, v := range m {
+			result[k] = v
+		}
+	case map[any]any:
+		for k, v := range m {
+			result[fmt.Sprint(k)] = v
+		}
+	}
+	return result
+}
+
+// saveConfigValues sets the given values in the config file, or in the named profile if profile isn't empty.
+// The keys given by remove are deleted.
+// The file is created if it doesn't exist, otherwise other values and comments are preserved.
+func saveConfigValues(path, profile string, values map[string]string, remove ...string) error {
+	doc := &yaml.Node{}
+	raw, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(raw, doc); err != nil {
+		return fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot update config file %s: not a YAML mapping", path)
+	}
+	target := root
+	if profile != "" {
+		target = mappingValue(mappingValue(root, profilesKey), profile)
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		node := mappingValue(target, key)
+		*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: values[key], LineComment: node.LineComment}
+	}
+	for _, key := range remove {
+		for i := 0; i+1 < len(target.Content); i += 2 {
+			if target.Content[i].Value == key {
+				target.Content = append(target.Content[:i], target.Content[i+2:]...)
+				break
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("cannot serialize config file: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// mappingValue returns the value node of the given key in the mapping node.
+// If the key doesn't exist, it's added with an empty mapping as value.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			if value.Tag == "!!null" {
+				// e.g. "profiles:" without entries
+				*value = yaml.Node{Kind: yaml.MappingNode}
+			}
+			return value
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
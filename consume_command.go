@@ -5,11 +5,15 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/ccremer/paperless-cli/pkg/consumer"
+	"github.com/ccremer/paperless-cli/pkg/hooks"
 	"github.com/ccremer/paperless-cli/pkg/paperless"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/urfave/cli/v2"
 )
 
@@ -22,6 +26,11 @@ type ConsumeCommand struct {
 
 	ConsumeDirName string
 	ConsumeDelay   time.Duration
+	MetricsAddress string
+	DryRun         bool
+
+	hooks   uploadHooks
+	metrics *consumer.Metrics
 }
 
 func newConsumeCommand() *ConsumeCommand {
@@ -32,37 +41,106 @@ func newConsumeCommand() *ConsumeCommand {
 		Before: loadConfigFileFn,
 		Action: actions(LogMetadata, c.Action),
 
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			newURLFlag(&c.PaperlessURL),
 			newUsernameFlag(&c.PaperlessUser),
 			newTokenFlag(&c.PaperlessToken),
 			newConsumeDirFlag(&c.ConsumeDirName),
 			newConsumeDelayFlag(&c.ConsumeDelay),
-		},
+			newMetricsAddressFlag(&c.MetricsAddress),
+			newDryRunFlag(&c.DryRun),
+		}, c.hooks.flags()...),
 	}
 	return c
 }
 
 func (c *ConsumeCommand) Action(ctx *cli.Context) error {
 	log := logr.FromContextOrDiscard(ctx.Context)
+	if c.DryRun {
+		return c.dryRun(ctx)
+	}
 	log.Info("Start consuming directory", "dir", c.ConsumeDirName)
 
-	clt := paperless.NewClient(c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	if err := c.hooks.setup(); err != nil {
+		return err
+	}
 	q := consumer.NewQueue[string]()
-	q.Subscribe(ctx.Context, func(fileName string) {
-		log.V(1).Info("Uploading file...", "file", fileName)
-		err := clt.Upload(ctx.Context, fileName, paperless.UploadParams{})
-		if err != nil {
-			log.Error(err, "Could not upload file")
-			return
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	c.metrics = consumer.NewMetrics(registry, q.Len)
+	var ready atomic.Bool
+	if c.MetricsAddress != "" {
+		if serveErr := consumer.StartServer(ctx.Context, c.MetricsAddress, consumer.NewHandler(registry, ready.Load)); serveErr != nil {
+			return serveErr
 		}
-		if deleteErr := os.Remove(fileName); deleteErr != nil {
-			log.Error(err, "Could not delete file, this might be re-uploaded later again", "file", fileName)
-		}
-		log.Info("File uploaded", "file", fileName)
+	}
+
+	q.Subscribe(ctx.Context, func(fileName string) {
+		c.upload(ctx, clt, fileName)
 	})
 
-	walkErr := filepath.WalkDir(c.ConsumeDirName, func(path string, entry fs.DirEntry, err error) error {
+	put := func(filePath string) {
+		c.metrics.FileDetected(filePath)
+		q.Put(filePath)
+	}
+	walkErr := c.walkConsumeDir(put)
+	if walkErr != nil {
+		return fmt.Errorf("cannot walk consumption dir: %w", walkErr)
+	}
+
+	watchErr := consumer.StartWatchingDir(ctx.Context, c.ConsumeDirName, c.ConsumeDelay, put)
+	if watchErr != nil {
+		return fmt.Errorf("cannot watch consumption dir: %w", watchErr)
+	}
+	ready.Store(true)
+	<-make(chan struct{})
+	return nil
+}
+
+// upload uploads the file and deletes it afterwards.
+func (c *ConsumeCommand) upload(ctx *cli.Context, clt *paperless.Client, fileName string) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	log.V(1).Info("Uploading file...", "file", fileName)
+	var size int64
+	if info, statErr := os.Stat(fileName); statErr == nil {
+		size = info.Size()
+	}
+	start := time.Now()
+	taskID, err := clt.UploadDocument(ctx.Context, fileName, paperless.UploadParams{})
+	c.metrics.ObserveUpload(fileName, start, size, err)
+	if err != nil {
+		log.Error(err, "Could not upload file", "file", fileName)
+		go c.notify(ctx, clt, fileName, "", err)
+		return
+	}
+	if deleteErr := os.Remove(fileName); deleteErr != nil {
+		log.Error(deleteErr, "Could not delete file, this might be re-uploaded later again", "file", fileName)
+	}
+	log.Info("File uploaded", "file", fileName, "task_id", taskID)
+	go c.notify(ctx, clt, fileName, taskID, nil)
+}
+
+// notify fires the hooks for the uploaded file.
+// It runs in the background, as it may wait for the consumption of the document.
+func (c *ConsumeCommand) notify(ctx *cli.Context, clt *paperless.Client, fileName, taskID string, uploadErr error) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	payload := c.hooks.outcome(ctx.Context, clt, fileName, taskID, uploadErr)
+	switch {
+	case payload.Event == hooks.EventDuplicate:
+		log.Info("File is a duplicate of an existing document", "file", fileName, "reason", payload.Error)
+	case payload.Event == hooks.EventFailure && uploadErr == nil:
+		log.Error(fmt.Errorf("%s", payload.Error), "Could not consume file", "file", fileName, "task_id", taskID)
+	}
+	c.hooks.fire(ctx.Context, payload)
+}
+
+// walkConsumeDir calls the given function for each file that currently exists in the consumption dir.
+func (c *ConsumeCommand) walkConsumeDir(fn func(path string)) error {
+	return filepath.WalkDir(c.ConsumeDirName, func(path string, entry fs.DirEntry, err error) error {
 		if path == c.ConsumeDirName {
 			return nil // same directory, not interesting
 		}
@@ -72,19 +150,22 @@ func (c *ConsumeCommand) Action(ctx *cli.Context) error {
 		if err != nil {
 			return fs.SkipDir
 		}
-		q.Put(path)
+		fn(path)
 		return nil
 	})
-	if walkErr != nil {
-		return fmt.Errorf("cannot walk consumption dir: %w", walkErr)
-	}
+}
 
-	watchErr := consumer.StartWatchingDir(ctx.Context, c.ConsumeDirName, c.ConsumeDelay, func(filePath string) {
-		q.Put(filePath)
+// dryRun prints the files that would currently be uploaded, without watching the consumption dir.
+func (c *ConsumeCommand) dryRun(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	count := 0
+	walkErr := c.walkConsumeDir(func(path string) {
+		log.Info("Would upload file and delete it afterwards", "file", path)
+		count++
 	})
-	if watchErr != nil {
-		return fmt.Errorf("cannot watch consumption dir: %w", watchErr)
+	if walkErr != nil {
+		return fmt.Errorf("cannot walk consumption dir: %w", walkErr)
 	}
-	<-make(chan struct{})
+	log.Info("Would upload files", "count", count)
 	return nil
 }
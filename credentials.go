@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ccremer/paperless-cli/pkg/credentials"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+// resolveTokens sets the token flags of the current command from the alternative credential sources if they are empty.
+// For each prefix, e.g. "source-", the sources are tried in this order: token file, token command and OS keyring.
+// values are the values of the config file, which provide the prefixed token file and command of profiles.
+func resolveTokens(ctx *cli.Context, values map[any]any, prefixes []string) error {
+	for _, prefix := range prefixes {
+		tokenFlag := prefix + newTokenFlag(nil).Name
+		if !hasFlag(ctx, tokenFlag) || ctx.String(tokenFlag) != "" {
+			continue
+		}
+		token, err := resolveToken(ctx, values, prefix)
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			continue
+		}
+		if err := ctx.Set(tokenFlag, token); err != nil {
+			return fmt.Errorf("cannot set --%s: %w", tokenFlag, err)
+		}
+	}
+	return nil
+}
+
+func resolveToken(ctx *cli.Context, values map[any]any, prefix string) (string, error) {
+	tokenFile, tokenCommand := ctx.String(newTokenFileFlag().Name), ctx.String(newTokenCommandFlag().Name)
+	if prefix != "" {
+		tokenFile, _ = values[prefix+newTokenFileFlag().Name].(string)
+		tokenCommand, _ = values[prefix+newTokenCommandFlag().Name].(string)
+	}
+	if tokenFile != "" {
+		return credentials.FromFile(tokenFile)
+	}
+	if tokenCommand != "" {
+		return credentials.FromCommand(ctx.Context, tokenCommand)
+	}
+
+	url := ctx.String(prefix + newURLFlag(nil).Name)
+	if url == "" {
+		return "", nil
+	}
+	usernameFlag := prefix + newUsernameFlag(nil).Name
+	token, apiToken, err := credentials.LookupKeyring(url, ctx.String(usernameFlag))
+	if err != nil {
+		// the keyring is optional, e.g. there is no Secret Service on headless machines.
+		logr.FromContextOrDiscard(ctx.Context).V(1).Info("No token found in keyring", "url", url, "error", err.Error())
+		return "", nil
+	}
+	if apiToken && ctx.String(usernameFlag) != "" {
+		// the API token stored by "login" replaces the password of BasicAuth, like with "login --store config"
+		if err := ctx.Set(usernameFlag, ""); err != nil {
+			return "", fmt.Errorf("cannot unset --%s: %w", usernameFlag, err)
+		}
+	}
+	return token, nil
+}
+
+// hasFlag returns true if the current command has a flag with the given name.
+func hasFlag(ctx *cli.Context, name string) bool {
+	for _, flag := range ctx.Command.Flags {
+		for _, flagName := range flag.Names() {
+			if flagName == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+type DocumentCommand struct {
+	cli.Command
+}
+
+func newDocumentCommand() *DocumentCommand {
+	c := &DocumentCommand{}
+	c.Command = cli.Command{
+		Name:  "document",
+		Usage: "Changes or deletes single documents",
+		Subcommands: []*cli.Command{
+			&newDocumentSetCommand().Command,
+			&newDocumentDeleteCommand().Command,
+		},
+	}
+	return c
+}
+
+// documentResult is the outcome of changing a single document.
+type documentResult struct {
+	ID    int    `json:"id"`
+	Title string `json:"title,omitempty"`
+	// Action is what happened to the document, e.g. "updated".
+	Action string `json:"action"`
+	// NewID is the ID of the document on the target, if it has been copied.
+	NewID int    `json:"new_id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func (r documentResult) failed(err error) documentResult {
+	r.Action = "failed"
+	r.Error = err.Error()
+	return r
+}
+
+// printDocumentResults prints the changed documents.
+// The text output is empty, since the changes are already logged.
+func printDocumentResults(ctx *cli.Context, results []documentResult) error {
+	return printResult(ctx, resultPrinter{
+		result: results,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"ID", "Title", "Action", "New ID", "Error"}}
+			for _, r := range results {
+				newID := ""
+				if r.NewID != 0 {
+					newID = strconv.Itoa(r.NewID)
+				}
+				data = append(data, []string{strconv.Itoa(r.ID), r.Title, r.Action, newID, r.Error})
+			}
+			return data
+		},
+		text: func(io.Writer) error { return nil },
+	})
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+type DocumentDeleteCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	Yes    bool
+	DryRun bool
+}
+
+func newDocumentDeleteCommand() *DocumentDeleteCommand {
+	c := &DocumentDeleteCommand{}
+	c.Command = cli.Command{
+		Name:      "delete",
+		Usage:     "Deletes the given document(s)",
+		Before:    before(requireArgs, loadConfigFileFn),
+		Action:    actions(LogMetadata, c.Action),
+		ArgsUsage: "ID...",
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newYesFlag(&c.Yes),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *DocumentDeleteCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	ids, err := parseDocumentIDs(ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	documents := make([]paperless.Document, 0, len(ids))
+	results := make([]documentResult, 0, len(ids))
+	for _, id := range ids {
+		doc, getErr := clt.GetDocument(ctx.Context, id)
+		if getErr != nil {
+			return errors.Wrap(getErr, "cannot get document %d", id)
+		}
+		documents = append(documents, *doc)
+		results = append(results, documentResult{ID: doc.ID, Title: doc.Title, Action: "deleted"})
+		if c.DryRun {
+			log.Info("Would delete document", "id", doc.ID, "title", doc.Title)
+		}
+	}
+	if c.DryRun {
+		return printDocumentResults(ctx, results)
+	}
+
+	confirmed, confirmErr := confirm(fmt.Sprintf("Delete %d document(s) %v?", len(ids), ids), c.Yes)
+	if confirmErr != nil {
+		return confirmErr
+	}
+	if !confirmed {
+		return fmt.Errorf("aborted")
+	}
+	for _, doc := range documents {
+		if deleteErr := clt.DeleteDocument(ctx.Context, doc.ID); deleteErr != nil {
+			return errors.Wrap(deleteErr, "cannot delete document %d", doc.ID)
+		}
+		log.Info("Document deleted", "id", doc.ID, "title", doc.Title)
+	}
+	return printDocumentResults(ctx, results)
+}
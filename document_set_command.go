@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+type DocumentSetCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	Title              string
+	Created            cli.Timestamp
+	Correspondent      string
+	DocumentType       string
+	StoragePath        string
+	SetTags            cli.StringSlice
+	AddTags            cli.StringSlice
+	RemoveTags         cli.StringSlice
+	ASN                string
+	SetCustomFields    cli.StringSlice
+	RemoveCustomFields cli.StringSlice
+	DryRun             bool
+}
+
+func newDocumentSetCommand() *DocumentSetCommand {
+	c := &DocumentSetCommand{}
+	c.Command = cli.Command{
+		Name:  "set",
+		Usage: "Changes the metadata of the given document(s)",
+		Description: `Only the fields given by flags are changed.
+Tags, correspondents, document types, storage paths and custom fields can be given by name or ID.`,
+		Before:    before(requireArgs, loadConfigFileFn),
+		Action:    actions(LogMetadata, c.Action),
+		ArgsUsage: "ID...",
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newSetTitleFlag(&c.Title),
+			newSetCreatedFlag(&c.Created),
+			newSetCorrespondentFlag(&c.Correspondent),
+			newSetDocumentTypeFlag(&c.DocumentType),
+			newSetStoragePathFlag(&c.StoragePath),
+			newSetTagsFlag(&c.SetTags),
+			newAddTagFlag(&c.AddTags),
+			newRemoveTagFlag(&c.RemoveTags),
+			newSetASNFlag(&c.ASN),
+			newSetCustomFieldFlag(&c.SetCustomFields),
+			newRemoveCustomFieldFlag(&c.RemoveCustomFields),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *DocumentSetCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	ids, err := parseDocumentIDs(ctx.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	resolver := paperless.NewResolver(clt)
+	results := make([]documentResult, 0, len(ids))
+	for _, id := range ids {
+		doc, getErr := clt.GetDocument(ctx.Context, id)
+		if getErr != nil {
+			return errors.Wrap(getErr, "cannot get document %d", id)
+		}
+		patch, patchErr := c.makePatch(ctx, resolver, *doc)
+		if patchErr != nil {
+			return patchErr
+		}
+		if len(patch) == 0 {
+			return showFlagError(ctx, fmt.Errorf("at least one field to change is required"))
+		}
+		if c.DryRun {
+			log.Info("Would update document", "id", id, "title", doc.Title, "patch", patch)
+			results = append(results, documentResult{ID: id, Title: doc.Title, Action: "updated"})
+			continue
+		}
+		if _, updateErr := clt.UpdateDocument(ctx.Context, id, patch); updateErr != nil {
+			return errors.Wrap(updateErr, "cannot update document %d", id)
+		}
+		log.Info("Document updated", "id", id, "title", doc.Title)
+		results = append(results, documentResult{ID: id, Title: doc.Title, Action: "updated"})
+	}
+	return printDocumentResults(ctx, results)
+}
+
+// makePatch returns the fields to change based on the current state of the document.
+func (c *DocumentSetCommand) makePatch(ctx *cli.Context, resolver *paperless.Resolver, doc paperless.Document) (paperless.DocumentPatch, error) {
+	patch := paperless.DocumentPatch{}
+	if ctx.IsSet(newSetTitleFlag(nil).Name) {
+		patch["title"] = c.Title
+	}
+	if created := c.Created.Value(); created != nil {
+		patch["created_date"] = created.Format("2006-01-02")
+	}
+	optionalIDs := []struct {
+		flag    string
+		field   string
+		value   string
+		resolve func(ctx context.Context, nameOrID string) (int, error)
+	}{
+		{flag: newSetCorrespondentFlag(nil).Name, field: "correspondent", value: c.Correspondent, resolve: resolver.CorrespondentID},
+		{flag: newSetDocumentTypeFlag(nil).Name, field: "document_type", value: c.DocumentType, resolve: resolver.DocumentTypeID},
+		{flag: newSetStoragePathFlag(nil).Name, field: "storage_path", value: c.StoragePath, resolve: resolver.StoragePathID},
+	}
+	for _, optional := range optionalIDs {
+		if !ctx.IsSet(optional.flag) {
+			continue
+		}
+		id, err := resolveOptionalID(ctx, optional.resolve, optional.value)
+		if err != nil {
+			return nil, err
+		}
+		patch[optional.field] = id
+	}
+	if ctx.IsSet(newSetASNFlag(nil).Name) {
+		asn, err := parseOptionalInt(c.ASN)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive serial number %q: %w", c.ASN, err)
+		}
+		patch["archive_serial_number"] = asn
+	}
+
+	tags, tagsChanged, err := c.getTags(ctx, resolver, doc.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if tagsChanged {
+		patch["tags"] = tags
+	}
+	fields, fieldsChanged, err := c.getCustomFields(ctx, resolver, doc.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	if fieldsChanged {
+		patch["custom_fields"] = fields
+	}
+	return patch, nil
+}
+
+// getTags returns the new list of tags, and whether any tag flag was given.
+func (c *DocumentSetCommand) getTags(ctx *cli.Context, resolver *paperless.Resolver, current []int) ([]int, bool, error) {
+	if !ctx.IsSet(newSetTagsFlag(nil).Name) && len(c.AddTags.Value()) == 0 && len(c.RemoveTags.Value()) == 0 {
+		return nil, false, nil
+	}
+	tags := slices.Clone(current)
+	if ctx.IsSet(newSetTagsFlag(nil).Name) {
+		setIDs, err := resolver.TagIDs(ctx.Context, c.SetTags.Value())
+		if err != nil {
+			return nil, false, err
+		}
+		tags = setIDs
+	}
+	addIDs, err := resolver.TagIDs(ctx.Context, c.AddTags.Value())
+	if err != nil {
+		return nil, false, err
+	}
+	for _, id := range addIDs {
+		if !slices.Contains(tags, id) {
+			tags = append(tags, id)
+		}
+	}
+	removeIDs, err := resolver.TagIDs(ctx.Context, c.RemoveTags.Value())
+	if err != nil {
+		return nil, false, err
+	}
+	tags = slices.DeleteFunc(tags, func(id int) bool {
+		return slices.Contains(removeIDs, id)
+	})
+	if tags == nil {
+		tags = []int{}
+	}
+	return tags, true, nil
+}
+
+// getCustomFields returns the new list of custom fields, and whether any custom field flag was given.
+func (c *DocumentSetCommand) getCustomFields(ctx *cli.Context, resolver *paperless.Resolver, current []paperless.CustomFieldInstance) ([]paperless.CustomFieldInstance, bool, error) {
+	if len(c.SetCustomFields.Value()) == 0 && len(c.RemoveCustomFields.Value()) == 0 {
+		return nil, false, nil
+	}
+	fields := slices.Clone(current)
+	values, err := resolveCustomFieldValues(ctx, resolver, c.SetCustomFields.Value())
+	if err != nil {
+		return nil, false, err
+	}
+	for id, value := range values {
+		i := slices.IndexFunc(fields, func(f paperless.CustomFieldInstance) bool { return f.Field == id })
+		if i >= 0 {
+			fields[i].Value = value
+		} else {
+			fields = append(fields, paperless.CustomFieldInstance{Field: id, Value: value})
+		}
+	}
+	for _, name := range c.RemoveCustomFields.Value() {
+		id, resolveErr := resolver.CustomFieldID(ctx.Context, name)
+		if resolveErr != nil {
+			return nil, false, resolveErr
+		}
+		fields = slices.DeleteFunc(fields, func(f paperless.CustomFieldInstance) bool { return f.Field == id })
+	}
+	if fields == nil {
+		fields = []paperless.CustomFieldInstance{}
+	}
+	return fields, true, nil
+}
+
+// parseDocumentIDs parses the given arguments as document IDs.
+func parseDocumentIDs(args []string) ([]int, error) {
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid document ID %q: %w", arg, err)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// parseOptionalInt returns nil if s is empty, otherwise the parsed number.
+func parseOptionalInt(s string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
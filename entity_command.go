@@ -0,0 +1,501 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/go-logr/logr"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+// entityKind describes a type of entity that is managed with the same set of subcommands.
+type entityKind struct {
+	// name is the name of the command, e.g. "document-type".
+	name string
+	// label is the human-readable name, e.g. "document type".
+	label string
+	// extraColumns are the headers of additional columns when listing.
+	extraColumns []string
+	// extraFlags returns additional flags for creating and updating.
+	extraFlags func(f *entityFields) []cli.Flag
+
+	list    func(ctx context.Context, clt *paperless.Client) ([]entityRow, error)
+	create  func(ctx context.Context, clt *paperless.Client, fields paperless.EntityPatch) (int, error)
+	update  func(ctx context.Context, clt *paperless.Client, id int, fields paperless.EntityPatch) error
+	delete  func(clt *paperless.Client, ctx context.Context, id int) error
+	resolve func(r *paperless.Resolver) func(ctx context.Context, nameOrID string) (int, error)
+}
+
+// entityRow is a single entity in a list.
+type entityRow struct {
+	ID            int
+	Name          string
+	Matching      paperless.Matching
+	DocumentCount int
+	Extra         []string
+	// entity is the entity as returned by the API, which is used for the JSON and YAML output.
+	entity any
+}
+
+var tagKind = entityKind{
+	name: "tag", label: "tag",
+	extraColumns: []string{"Color", "Inbox"},
+	extraFlags: func(f *entityFields) []cli.Flag {
+		return []cli.Flag{newColorFlag(&f.Color), newInboxTagFlag(&f.InboxTag)}
+	},
+	list: func(ctx context.Context, clt *paperless.Client) ([]entityRow, error) {
+		return mapToRows(clt.QueryTags(ctx))(func(e paperless.Tag) entityRow {
+			return entityRow{ID: e.ID, Name: e.Name, Matching: e.Matching, DocumentCount: e.DocumentCount,
+				Extra: []string{e.Color, strconv.FormatBool(e.IsInboxTag)}}
+		})
+	},
+	create: func(ctx context.Context, clt *paperless.Client, fields paperless.EntityPatch) (int, error) {
+		e, err := clt.CreateTag(ctx, fields)
+		return idOf(e, err, func(e *paperless.Tag) int { return e.ID })
+	},
+	update: func(ctx context.Context, clt *paperless.Client, id int, fields paperless.EntityPatch) error {
+		_, err := clt.UpdateTag(ctx, id, fields)
+		return err
+	},
+	delete:  (*paperless.Client).DeleteTag,
+	resolve: func(r *paperless.Resolver) func(context.Context, string) (int, error) { return r.TagID },
+}
+
+var correspondentKind = entityKind{
+	name: "correspondent", label: "correspondent",
+	list: func(ctx context.Context, clt *paperless.Client) ([]entityRow, error) {
+		return mapToRows(clt.QueryCorrespondents(ctx))(func(e paperless.Correspondent) entityRow {
+			return entityRow{ID: e.ID, Name: e.Name, Matching: e.Matching, DocumentCount: e.DocumentCount}
+		})
+	},
+	create: func(ctx context.Context, clt *paperless.Client, fields paperless.EntityPatch) (int, error) {
+		e, err := clt.CreateCorrespondent(ctx, fields)
+		return idOf(e, err, func(e *paperless.Correspondent) int { return e.ID })
+	},
+	update: func(ctx context.Context, clt *paperless.Client, id int, fields paperless.EntityPatch) error {
+		_, err := clt.UpdateCorrespondent(ctx, id, fields)
+		return err
+	},
+	delete:  (*paperless.Client).DeleteCorrespondent,
+	resolve: func(r *paperless.Resolver) func(context.Context, string) (int, error) { return r.CorrespondentID },
+}
+
+var documentTypeKind = entityKind{
+	name: "document-type", label: "document type",
+	list: func(ctx context.Context, clt *paperless.Client) ([]entityRow, error) {
+		return mapToRows(clt.QueryDocumentTypes(ctx))(func(e paperless.DocumentType) entityRow {
+			return entityRow{ID: e.ID, Name: e.Name, Matching: e.Matching, DocumentCount: e.DocumentCount}
+		})
+	},
+	create: func(ctx context.Context, clt *paperless.Client, fields paperless.EntityPatch) (int, error) {
+		e, err := clt.CreateDocumentType(ctx, fields)
+		return idOf(e, err, func(e *paperless.DocumentType) int { return e.ID })
+	},
+	update: func(ctx context.Context, clt *paperless.Client, id int, fields paperless.EntityPatch) error {
+		_, err := clt.UpdateDocumentType(ctx, id, fields)
+		return err
+	},
+	delete:  (*paperless.Client).DeleteDocumentType,
+	resolve: func(r *paperless.Resolver) func(context.Context, string) (int, error) { return r.DocumentTypeID },
+}
+
+var storagePathKind = entityKind{
+	name: "storage-path", label: "storage path",
+	extraColumns: []string{"Path"},
+	extraFlags: func(f *entityFields) []cli.Flag {
+		return []cli.Flag{newStoragePathFlag(&f.Path)}
+	},
+	list: func(ctx context.Context, clt *paperless.Client) ([]entityRow, error) {
+		return mapToRows(clt.QueryStoragePaths(ctx))(func(e paperless.StoragePath) entityRow {
+			return entityRow{ID: e.ID, Name: e.Name, Matching: e.Matching, DocumentCount: e.DocumentCount, Extra: []string{e.Path}}
+		})
+	},
+	create: func(ctx context.Context, clt *paperless.Client, fields paperless.EntityPatch) (int, error) {
+		e, err := clt.CreateStoragePath(ctx, fields)
+		return idOf(e, err, func(e *paperless.StoragePath) int { return e.ID })
+	},
+	update: func(ctx context.Context, clt *paperless.Client, id int, fields paperless.EntityPatch) error {
+		_, err := clt.UpdateStoragePath(ctx, id, fields)
+		return err
+	},
+	delete:  (*paperless.Client).DeleteStoragePath,
+	resolve: func(r *paperless.Resolver) func(context.Context, string) (int, error) { return r.StoragePathID },
+}
+
+// entityFields contains the flag values for creating or updating an entity.
+type entityFields struct {
+	Name              string
+	Match             string
+	MatchingAlgorithm string
+	CaseInsensitive   bool
+	Color             string
+	InboxTag          bool
+	Path              string
+}
+
+func (f *entityFields) flags(kind entityKind, withName bool) []cli.Flag {
+	flags := make([]cli.Flag, 0)
+	if withName {
+		flags = append(flags, newNameFlag(&f.Name))
+	}
+	flags = append(flags,
+		newMatchFlag(&f.Match),
+		newMatchingAlgorithmFlag(&f.MatchingAlgorithm),
+		newCaseInsensitiveFlag(&f.CaseInsensitive),
+	)
+	if kind.extraFlags != nil {
+		flags = append(flags, kind.extraFlags(f)...)
+	}
+	return flags
+}
+
+// toPatch returns the fields of all flags that have been set.
+func (f *entityFields) toPatch(ctx *cli.Context) (paperless.EntityPatch, error) {
+	patch := paperless.EntityPatch{}
+	setIfGiven := func(flag string, field string, value any) {
+		if ctx.IsSet(flag) {
+			patch[field] = value
+		}
+	}
+	setIfGiven(newNameFlag(nil).Name, "name", f.Name)
+	setIfGiven(newMatchFlag(nil).Name, "match", f.Match)
+	setIfGiven(newCaseInsensitiveFlag(nil).Name, "is_insensitive", f.CaseInsensitive)
+	setIfGiven(newColorFlag(nil).Name, "color", f.Color)
+	setIfGiven(newInboxTagFlag(nil).Name, "is_inbox_tag", f.InboxTag)
+	setIfGiven(newStoragePathFlag(nil).Name, "path", f.Path)
+	if ctx.IsSet(newMatchingAlgorithmFlag(nil).Name) {
+		algorithm, err := paperless.ParseMatchingAlgorithm(f.MatchingAlgorithm)
+		if err != nil {
+			return nil, err
+		}
+		patch["matching_algorithm"] = algorithm
+	}
+	return patch, nil
+}
+
+type EntityCommand struct {
+	cli.Command
+}
+
+func newEntityCommand(kind entityKind) *EntityCommand {
+	c := &EntityCommand{}
+	c.Command = cli.Command{
+		Name:  kind.name,
+		Usage: fmt.Sprintf("Manages %ss", kind.label),
+		Subcommands: []*cli.Command{
+			&newEntityListCommand(kind).Command,
+			&newEntityCreateCommand(kind).Command,
+			&newEntityUpdateCommand(kind).Command,
+			&newEntityDeleteCommand(kind).Command,
+		},
+	}
+	return c
+}
+
+type EntityListCommand struct {
+	cli.Command
+	kind entityKind
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+}
+
+func newEntityListCommand(kind entityKind) *EntityListCommand {
+	c := &EntityListCommand{kind: kind}
+	c.Command = cli.Command{
+		Name:   "list",
+		Usage:  fmt.Sprintf("Lists all %ss", kind.label),
+		Before: loadConfigFileFn,
+		Action: c.Action,
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+		},
+	}
+	return c
+}
+
+func (c *EntityListCommand) Action(ctx *cli.Context) error {
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	rows, err := c.kind.list(ctx.Context, clt)
+	if err != nil {
+		return errors.Wrap(err, "cannot query %ss", c.kind.label)
+	}
+	entities := make([]any, len(rows))
+	for i, row := range rows {
+		entities[i] = row.entity
+	}
+	return printResult(ctx, resultPrinter{
+		result: entities,
+		table: func() pterm.TableData {
+			header := append([]string{"ID", "Name", "Match", "Algorithm", "Case-insensitive", "Documents"}, c.kind.extraColumns...)
+			data := pterm.TableData{header}
+			for _, row := range rows {
+				data = append(data, append([]string{
+					strconv.Itoa(row.ID),
+					row.Name,
+					row.Matching.Match,
+					row.Matching.MatchingAlgorithm.String(),
+					strconv.FormatBool(row.Matching.IsInsensitive),
+					strconv.Itoa(row.DocumentCount),
+				}, row.Extra...))
+			}
+			return data
+		},
+	})
+}
+
+// entityResult is the outcome of creating, updating or deleting entities.
+type entityResult struct {
+	ID   int    `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// printEntityResults prints the affected entities.
+// The text output is empty, since the changes are already logged.
+func printEntityResults(ctx *cli.Context, results []entityResult) error {
+	return printResult(ctx, resultPrinter{
+		result: results,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"ID", "Name"}}
+			for _, r := range results {
+				data = append(data, []string{strconv.Itoa(r.ID), r.Name})
+			}
+			return data
+		},
+		text: func(io.Writer) error { return nil },
+	})
+}
+
+type EntityCreateCommand struct {
+	cli.Command
+	kind entityKind
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	fields entityFields
+}
+
+func newEntityCreateCommand(kind entityKind) *EntityCreateCommand {
+	c := &EntityCreateCommand{kind: kind}
+	c.Command = cli.Command{
+		Name:      "create",
+		Usage:     fmt.Sprintf("Creates a new %s", kind.label),
+		Before:    before(requireArgs, loadConfigFileFn),
+		Action:    c.Action,
+		ArgsUsage: "NAME",
+		Flags: append([]cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+		}, c.fields.flags(kind, false)...),
+	}
+	return c
+}
+
+func (c *EntityCreateCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	patch, err := c.fields.toPatch(ctx)
+	if err != nil {
+		return err
+	}
+	patch["name"] = ctx.Args().First()
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	id, err := c.kind.create(ctx.Context, clt, patch)
+	if err != nil {
+		return errors.Wrap(err, "cannot create %s", c.kind.label)
+	}
+	log.Info("Created "+c.kind.label, "id", id, "name", patch["name"])
+	return printEntityResults(ctx, []entityResult{{ID: id, Name: ctx.Args().First()}})
+}
+
+type EntityUpdateCommand struct {
+	cli.Command
+	kind entityKind
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	fields entityFields
+}
+
+func newEntityUpdateCommand(kind entityKind) *EntityUpdateCommand {
+	c := &EntityUpdateCommand{kind: kind}
+	c.Command = cli.Command{
+		Name:         "update",
+		Usage:        fmt.Sprintf("Changes an existing %s", kind.label),
+		Before:       before(requireArgs, loadConfigFileFn),
+		Action:       c.Action,
+		ArgsUsage:    "NAME|ID",
+		BashComplete: completeEntityNames(kind),
+		Flags: append([]cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+		}, c.fields.flags(kind, true)...),
+	}
+	return c
+}
+
+func (c *EntityUpdateCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	patch, err := c.fields.toPatch(ctx)
+	if err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		return showFlagError(ctx, fmt.Errorf("at least one field to change is required"))
+	}
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	id, err := c.kind.resolve(paperless.NewResolver(clt))(ctx.Context, ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	names, err := c.kind.namesByID(ctx.Context, clt)
+	if err != nil {
+		return err
+	}
+	if updateErr := c.kind.update(ctx.Context, clt, id, patch); updateErr != nil {
+		return errors.Wrap(updateErr, "cannot update %s %d %q", c.kind.label, id, names[id])
+	}
+	log.Info("Updated "+c.kind.label, "id", id, "name", names[id])
+	result := entityResult{ID: id, Name: names[id]}
+	if newName, renamed := patch["name"].(string); renamed {
+		result.Name = newName
+	}
+	return printEntityResults(ctx, []entityResult{result})
+}
+
+type EntityDeleteCommand struct {
+	cli.Command
+	kind entityKind
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	Yes    bool
+	DryRun bool
+}
+
+func newEntityDeleteCommand(kind entityKind) *EntityDeleteCommand {
+	c := &EntityDeleteCommand{kind: kind}
+	c.Command = cli.Command{
+		Name:         "delete",
+		Usage:        fmt.Sprintf("Deletes the given %s(s)", kind.label),
+		Before:       before(requireArgs, loadConfigFileFn),
+		Action:       c.Action,
+		ArgsUsage:    "NAME|ID...",
+		BashComplete: completeEntityNames(kind),
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newYesFlag(&c.Yes),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *EntityDeleteCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	resolve := c.kind.resolve(paperless.NewResolver(clt))
+	names, err := c.kind.namesByID(ctx.Context, clt)
+	if err != nil {
+		return err
+	}
+
+	results := make([]entityResult, 0, ctx.NArg())
+	descriptions := make([]string, 0, ctx.NArg())
+	for _, arg := range ctx.Args().Slice() {
+		id, err := resolve(ctx.Context, arg)
+		if err != nil {
+			return err
+		}
+		results = append(results, entityResult{ID: id, Name: names[id]})
+		descriptions = append(descriptions, fmt.Sprintf("%d %q", id, names[id]))
+	}
+	if c.DryRun {
+		for _, r := range results {
+			log.Info("Would delete "+c.kind.label, "id", r.ID, "name", r.Name)
+		}
+		return printEntityResults(ctx, results)
+	}
+	confirmed, confirmErr := confirm(fmt.Sprintf("Delete %d %s(s): %s?", len(results), c.kind.label, strings.Join(descriptions, ", ")), c.Yes)
+	if confirmErr != nil {
+		return confirmErr
+	}
+	if !confirmed {
+		return fmt.Errorf("aborted")
+	}
+	for _, r := range results {
+		if err := c.kind.delete(clt, ctx.Context, r.ID); err != nil {
+			return errors.Wrap(err, "cannot delete %s %d %q", c.kind.label, r.ID, r.Name)
+		}
+		log.Info("Deleted "+c.kind.label, "id", r.ID, "name", r.Name)
+	}
+	return printEntityResults(ctx, results)
+}
+
+// namesByID returns the names of all entities of the kind by their ID, e.g. to show which entities are about to change.
+func (k entityKind) namesByID(ctx context.Context, clt *paperless.Client) (map[int]string, error) {
+	rows, err := k.list(ctx, clt)
+	if err != nil {
+		return nil, errors.Wrap(err, "cannot query %ss", k.label)
+	}
+	names := make(map[int]string, len(rows))
+	for _, row := range rows {
+		names[row.ID] = row.Name
+	}
+	return names, nil
+}
+
+// mapToRows converts the result of a query to rows.
+func mapToRows[T any](entities []T, err error) func(fn func(T) entityRow) ([]entityRow, error) {
+	return func(fn func(T) entityRow) ([]entityRow, error) {
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]entityRow, len(entities))
+		for i, entity := range entities {
+			rows[i] = fn(entity)
+			rows[i].entity = entity
+		}
+		return rows, nil
+	}
+}
+
+// idOf returns the ID of the given entity unless err is not nil.
+func idOf[T any](entity *T, err error, fn func(*T) int) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	return fn(entity), nil
+}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"fmt"
-	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/hooks"
+	"github.com/ccremer/paperless-cli/pkg/oidc"
 	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/safedelete"
+	"github.com/ccremer/paperless-cli/pkg/transport"
 	"github.com/urfave/cli/v2"
 	"github.com/urfave/cli/v2/altsrc"
 )
@@ -20,6 +25,30 @@ func newConfigFileFlag() *cli.StringFlag {
 	}
 }
 
+func newProfileFlag() *cli.StringFlag {
+	return &cli.StringFlag{
+		Name: "profile", EnvVars: envVars("PROFILE"),
+		Aliases: []string{"P"},
+		Usage:   `name of the profile in the config file to use, e.g. "home". Defaults to the "profile" key in the config file.`,
+	}
+}
+
+func newSourceProfileFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name: "source-profile", EnvVars: envVars("SOURCE_PROFILE"),
+		Usage:       fmt.Sprintf("name of the profile in the config file to use for the --%s, --%s and --%s flags.", "source-url", "source-username", "source-token"),
+		Destination: dest,
+	}
+}
+
+func newTargetProfileFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name: "target-profile", EnvVars: envVars("TARGET_PROFILE"),
+		Usage:       fmt.Sprintf("name of the profile in the config file to use for the --%s, --%s and --%s flags.", "target-url", "target-username", "target-token"),
+		Destination: dest,
+	}
+}
+
 func newLogLevelFlag() *altsrc.IntFlag {
 	return altsrc.NewIntFlag(&cli.IntFlag{
 		Name: "log-level", Aliases: []string{"v"}, EnvVars: []string{"LOG_LEVEL"},
@@ -28,6 +57,34 @@ func newLogLevelFlag() *altsrc.IntFlag {
 	})
 }
 
+func newLogFormatFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "log-format", EnvVars: []string{"LOG_FORMAT"},
+		Usage: fmt.Sprintf("format of the logs written to stderr, one of [%s]. Defaults to %s if stderr is a terminal, otherwise %s.",
+			strings.Join(logFormats, ", "), logFormatConsole, logFormatJSON),
+		Action: func(ctx *cli.Context, s string) error {
+			if !slices.Contains(logFormats, s) {
+				return showFlagError(ctx, fmt.Errorf("unsupported log format: %q", s))
+			}
+			return nil
+		},
+	})
+}
+
+func newOutputFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "output", Aliases: []string{"o"}, EnvVars: envVars("OUTPUT"),
+		Usage: fmt.Sprintf("format of the command results written to stdout, one of [%s].", strings.Join(outputFormats, ", ")),
+		Value: outputText,
+		Action: func(ctx *cli.Context, s string) error {
+			if !slices.Contains(outputFormats, s) {
+				return showFlagError(ctx, fmt.Errorf("unsupported output format: %q", s))
+			}
+			return nil
+		},
+	})
+}
+
 func newURLFlag(dest *string) *altsrc.StringFlag {
 	return altsrc.NewStringFlag(&cli.StringFlag{
 		Name: "url", EnvVars: envVars("URL"),
@@ -46,6 +103,167 @@ func newTokenFlag(dest *string) *altsrc.StringFlag {
 	})
 }
 
+func newTokenFileFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "token-file", EnvVars: envVars("TOKEN_FILE"),
+		Usage: "path to a file containing the password or token, e.g. a Docker or systemd secret. Used if --token is empty.",
+	})
+}
+
+func newTokenCommandFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "token-command", EnvVars: envVars("TOKEN_COMMAND"),
+		Usage: `shell command that prints the password or token, e.g. "pass show paperless". Used if --token and --token-file are empty.`,
+	})
+}
+
+func newCAFileFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "ca-file", EnvVars: envVars("CA_FILE"),
+		Usage: "path to a PEM file with additional CA certificates to trust, e.g. of an internal CA.",
+	})
+}
+
+func newClientCertFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "client-cert", EnvVars: envVars("CLIENT_CERT"),
+		Usage: "path to a PEM file with the client certificate for mutual TLS. Requires --client-key.",
+	})
+}
+
+func newClientKeyFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "client-key", EnvVars: envVars("CLIENT_KEY"),
+		Usage: "path to a PEM file with the private key of the client certificate.",
+	})
+}
+
+func newTLSMinVersionFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "tls-min-version", EnvVars: envVars("TLS_MIN_VERSION"),
+		Usage: fmt.Sprintf("minimum TLS version, one of [%s].", strings.Join(transport.TLSVersionNames(), ", ")),
+		Action: func(ctx *cli.Context, s string) error {
+			if _, err := transport.ParseTLSVersion(s); err != nil {
+				return showFlagError(ctx, err)
+			}
+			return nil
+		},
+	})
+}
+
+func newInsecureSkipVerifyFlag() *altsrc.BoolFlag {
+	return altsrc.NewBoolFlag(&cli.BoolFlag{
+		Name: "insecure-skip-verify", EnvVars: envVars("INSECURE_SKIP_VERIFY"),
+		Usage: "disable the verification of the server certificate. Only use it for testing.",
+	})
+}
+
+func newProxyFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "proxy", EnvVars: envVars("PROXY"),
+		Usage: `URL of the HTTP proxy, e.g. "http://proxy:3128". Defaults to the HTTPS_PROXY and HTTP_PROXY environment variables.`,
+		Action: func(ctx *cli.Context, s string) error {
+			if _, err := transport.ParseProxy(s); err != nil {
+				return showFlagError(ctx, err)
+			}
+			return nil
+		},
+	})
+}
+
+func newConnectTimeoutFlag() *altsrc.DurationFlag {
+	return altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name: "connect-timeout", EnvVars: envVars("CONNECT_TIMEOUT"),
+		Usage: "maximum time to establish a connection to the paperless instance, including the TLS handshake.",
+		Value: 30 * time.Second,
+	})
+}
+
+func newRequestTimeoutFlag() *altsrc.DurationFlag {
+	return altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name: "request-timeout", EnvVars: envVars("REQUEST_TIMEOUT"),
+		Usage: "maximum time of a single request, including the download of documents. 0 disables the timeout.",
+	})
+}
+
+func newReadTimeoutFlag() *altsrc.DurationFlag {
+	return altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name: "read-timeout", EnvVars: envVars("READ_TIMEOUT"),
+		Usage: "maximum time to wait for data from the paperless instance, e.g. if it stalls. Doesn't limit the duration of downloads, " +
+			"but includes the time the server needs to prepare a bulk download before sending it. 0 disables the timeout.",
+	})
+}
+
+func newIdleTimeoutFlag() *altsrc.DurationFlag {
+	return altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name: "idle-timeout", EnvVars: envVars("IDLE_TIMEOUT"),
+		Usage: "maximum time an idle connection is kept open for reuse.",
+		Value: 90 * time.Second,
+	})
+}
+
+func newHeaderFlag() *altsrc.StringSliceFlag {
+	return altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+		Name: "header", EnvVars: envVars("HEADER"),
+		Usage: `additional HTTP header in the form "Name: value", e.g. for an authenticating proxy. Can be given multiple times.`,
+		Action: func(ctx *cli.Context, headers []string) error {
+			if _, err := transport.ParseHeaders(headers); err != nil {
+				return showFlagError(ctx, err)
+			}
+			return nil
+		},
+	})
+}
+
+func newBearerTokenFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "bearer-token", EnvVars: envVars("BEARER_TOKEN"),
+		Usage: "static bearer token to authenticate at an OIDC-aware proxy in front of the paperless instance. Replaces --token.",
+	})
+}
+
+func newOIDCIssuerFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "oidc-issuer", EnvVars: envVars("OIDC_ISSUER"),
+		Usage: "URL of the OIDC provider to obtain bearer tokens from, e.g. \"https://sso.example.com/realms/main\". Replaces --token.",
+	})
+}
+
+func newOIDCClientIDFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "oidc-client-id", EnvVars: envVars("OIDC_CLIENT_ID"),
+		Usage: "client ID at the OIDC provider.",
+	})
+}
+
+func newOIDCClientSecretFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "oidc-client-secret", EnvVars: envVars("OIDC_CLIENT_SECRET"),
+		Usage: "client secret at the OIDC provider. Required for the client-credentials flow.",
+	})
+}
+
+func newOIDCScopeFlag() *altsrc.StringSliceFlag {
+	return altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+		Name: "oidc-scope", EnvVars: envVars("OIDC_SCOPE"),
+		Usage: "scope to request from the OIDC provider. Can be given multiple times.",
+	})
+}
+
+func newOIDCFlowFlag() *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "oidc-flow", EnvVars: envVars("OIDC_FLOW"),
+		Usage: fmt.Sprintf("how to obtain bearer tokens from the OIDC provider, one of [%s, %s].", oidc.FlowClientCredentials, oidc.FlowDeviceCode),
+		Value: string(oidc.FlowClientCredentials),
+		Action: func(ctx *cli.Context, s string) error {
+			if _, err := oidc.ParseFlow(s); err != nil {
+				return showFlagError(ctx, err)
+			}
+			return nil
+		},
+	})
+}
+
 func newUsernameFlag(dest *string) *altsrc.StringFlag {
 	return altsrc.NewStringFlag(&cli.StringFlag{
 		Name: "username", EnvVars: envVars("USERNAME"),
@@ -54,6 +272,67 @@ func newUsernameFlag(dest *string) *altsrc.StringFlag {
 	})
 }
 
+func newSourceURLFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "source-url", EnvVars: envVars("SOURCE_URL"),
+		Usage:       "URL endpoint of the paperless instance to copy from.",
+		Action:      checkEmptyString("source-url"),
+		Destination: dest,
+	})
+}
+
+func newSourceTokenFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "source-token", EnvVars: envVars("SOURCE_TOKEN"),
+		Usage:       "password or token of the paperless instance to copy from.",
+		Action:      checkEmptyString("source-token"),
+		Destination: dest,
+	})
+}
+
+func newSourceUsernameFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "source-username", EnvVars: envVars("SOURCE_USERNAME"),
+		Usage:       "username for BasicAuth of the paperless instance to copy from. Leave empty to use token authentication.",
+		Destination: dest,
+	})
+}
+
+func newTargetURLFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "target-url", EnvVars: envVars("TARGET_URL"),
+		Usage:       "URL endpoint of the paperless instance to copy to.",
+		Action:      checkEmptyString("target-url"),
+		Destination: dest,
+	})
+}
+
+func newTargetTokenFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "target-token", EnvVars: envVars("TARGET_TOKEN"),
+		Usage:       "password or token of the paperless instance to copy to.",
+		Action:      checkEmptyString("target-token"),
+		Destination: dest,
+	})
+}
+
+func newTargetUsernameFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "target-username", EnvVars: envVars("TARGET_USERNAME"),
+		Usage:       "username for BasicAuth of the paperless instance to copy to. Leave empty to use token authentication.",
+		Destination: dest,
+	})
+}
+
+func newSyncStateFileFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "state-file", EnvVars: envVars("SYNC_STATE_FILE"),
+		Usage:       "path to the file that keeps track of the copied documents.",
+		Value:       "paperless-sync.json",
+		Destination: dest,
+	})
+}
+
 func newCreatedAtFlag(dest *cli.Timestamp) *cli.TimestampFlag {
 	return &cli.TimestampFlag{
 		Name:        "created-at",
@@ -100,6 +379,46 @@ func newDeleteAfterUploadFlag(dest *bool) *cli.BoolFlag {
 	}
 }
 
+func newWebhookURLFlag(dest *cli.StringSlice) *altsrc.StringSliceFlag {
+	return altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+		Name: "webhook-url", EnvVars: envVars("WEBHOOK_URL"),
+		Usage:       "URL to POST a JSON payload to for each upload event. Can be given multiple times.",
+		Destination: dest,
+	})
+}
+
+func newExecHookFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "exec-hook", EnvVars: envVars("EXEC_HOOK"),
+		Usage:       "shell command to run for each upload event, with the event data in PAPERLESS_EVENT, PAPERLESS_FILE, PAPERLESS_TASK_ID, PAPERLESS_DOCUMENT_ID and PAPERLESS_ERROR.",
+		Destination: dest,
+	})
+}
+
+func newHookEventFlag(dest *cli.StringSlice) *altsrc.StringSliceFlag {
+	return altsrc.NewStringSliceFlag(&cli.StringSliceFlag{
+		Name: "hook-event", EnvVars: envVars("HOOK_EVENT"),
+		Usage:       fmt.Sprintf("upload event(s) that fire the hooks, any of %v. All events if not given.", hooks.Events),
+		Destination: dest,
+		Action: func(ctx *cli.Context, events []string) error {
+			for _, event := range events {
+				if _, err := hooks.ParseEvent(event); err != nil {
+					return showFlagError(ctx, err)
+				}
+			}
+			return nil
+		},
+	})
+}
+
+func newWaitFlag(dest *bool) *altsrc.BoolFlag {
+	return altsrc.NewBoolFlag(&cli.BoolFlag{
+		Name: "wait", EnvVars: envVars("WAIT"),
+		Usage:       "wait until Paperless consumed each uploaded file, to get the document ID and detect duplicates.",
+		Destination: dest,
+	})
+}
+
 func newConsumeDirFlag(dest *string) *altsrc.StringFlag {
 	return altsrc.NewStringFlag(&cli.StringFlag{
 		Name: "consume-dir", EnvVars: []string{"CONSUME_DIR"},
@@ -180,13 +499,406 @@ func newIncrementalFlag(dest *bool) *altsrc.BoolFlag {
 	})
 }
 
-func loadConfigFileFn(ctx *cli.Context) error {
-	path := ctx.String(newConfigFileFlag().Name)
-	flags := ctx.Command.Flags
-	if _, err := os.Stat(path); err != nil && os.IsNotExist(err) {
-		return nil
+func newDryRunFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "dry-run",
+		Usage:       "only print what would be done, without changing anything on the server or on the disk.",
+		Destination: dest,
+	}
+}
+
+func newWithTokenFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "with-token",
+		Usage:       "read an existing API token instead of exchanging username and password for one.",
+		Destination: dest,
+	}
+}
+
+func newCredentialStoreFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "store",
+		Usage:       fmt.Sprintf("where to store the token, one of [%s, %s]. %q writes the URL and token to the config file, in the profile given by --profile, if any.", storeKeyring, storeConfig, storeConfig),
+		Value:       storeKeyring,
+		Destination: dest,
+		Action: func(ctx *cli.Context, s string) error {
+			if s != storeKeyring && s != storeConfig {
+				return showFlagError(ctx, fmt.Errorf("unknown store %q", s))
+			}
+			return nil
+		},
+	}
+}
+
+func newYesFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name: "yes", Aliases: []string{"y"},
+		Usage:       "confirm destructive operations without asking.",
+		Destination: dest,
+	}
+}
+
+func newDocumentIDFlag(dest *cli.IntSlice) *cli.IntSliceFlag {
+	return &cli.IntSliceFlag{
+		Name:        "id",
+		Usage:       "select the document(s) by ID.",
+		Destination: dest,
+	}
+}
+
+func newQueryFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "query",
+		Usage:       `select the documents matching the full text search query, e.g. "tag:invoice created:[2020 to 2021]".`,
+		Destination: dest,
+	}
+}
+
+func newAddTagFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "add-tag",
+		Usage:       "add the tag(s) given by name or ID.",
+		Destination: dest,
+	}
+}
+
+func newRemoveTagFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "remove-tag",
+		Usage:       "remove the tag(s) given by name or ID.",
+		Destination: dest,
+	}
+}
+
+func newSetTitleFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "set-title",
+		Usage:       "set the title.",
+		Destination: dest,
+	}
+}
+
+func newSetCreatedFlag(dest *cli.Timestamp) *cli.TimestampFlag {
+	return &cli.TimestampFlag{
+		Name:        "set-created",
+		Usage:       `set the "created" date.`,
+		Layout:      "2006-01-02",
+		Destination: dest,
+	}
+}
+
+func newSetTagsFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "set-tags",
+		Usage:       fmt.Sprintf("replace all tags with the tag(s) given by name or ID. Can be combined with --%s and --%s.", newAddTagFlag(nil).Name, newRemoveTagFlag(nil).Name),
+		Destination: dest,
+	}
+}
+
+func newSetASNFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "set-asn",
+		Usage:       "set the archive serial number. Set to empty string to remove the number.",
+		Destination: dest,
+	}
+}
+
+func newSetCorrespondentFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "set-correspondent",
+		Usage:       "set the correspondent given by name or ID. Set to empty string to remove the correspondent.",
+		Destination: dest,
+	}
+}
+
+func newSetDocumentTypeFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "set-type",
+		Usage:       "set the document type given by name or ID. Set to empty string to remove the document type.",
+		Destination: dest,
+	}
+}
+
+func newSetStoragePathFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "set-storage-path",
+		Usage:       "set the storage path given by name or ID. Set to empty string to remove the storage path.",
+		Destination: dest,
+	}
+}
+
+func newSetCustomFieldFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "set-custom-field",
+		Usage:       `set the custom field(s) given by name or ID to a value, in the form "NAME=VALUE".`,
+		Destination: dest,
+	}
+}
+
+func newRemoveCustomFieldFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "remove-custom-field",
+		Usage:       "remove the custom field(s) given by name or ID.",
+		Destination: dest,
+	}
+}
+
+func newRotateFlag(dest *int) *cli.IntFlag {
+	return &cli.IntFlag{
+		Name:        "rotate",
+		Usage:       "rotate the documents clockwise by the given degrees (90, 180 or 270).",
+		Destination: dest,
+		Action: func(ctx *cli.Context, degrees int) error {
+			if degrees != 90 && degrees != 180 && degrees != 270 {
+				return showFlagError(ctx, fmt.Errorf("parameter %q must be one of [90, 180, 270]", "rotate"))
+			}
+			return nil
+		},
+	}
+}
+
+func newMergeFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "merge",
+		Usage:       "merge the documents into a new document.",
+		Destination: dest,
+	}
+}
+
+func newReprocessFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "reprocess",
+		Usage:       "reprocess the documents, e.g. to run OCR again.",
+		Destination: dest,
+	}
+}
+
+func newDeleteFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "delete",
+		Usage:       "delete the documents. Asks for confirmation unless --yes is given.",
+		Destination: dest,
+	}
+}
+
+func newNameFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "name",
+		Usage:       "set the name.",
+		Destination: dest,
+	}
+}
+
+func newMatchFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "match",
+		Usage:       "set the text or pattern to automatically assign new documents.",
+		Destination: dest,
+	}
+}
+
+func newMatchingAlgorithmFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "matching-algorithm",
+		Usage:       fmt.Sprintf("set the algorithm to apply --%s, one of [%s].", newMatchFlag(nil).Name, strings.Join(paperless.MatchingAlgorithmNames(), ", ")),
+		Destination: dest,
+		Action: func(ctx *cli.Context, s string) error {
+			_, err := paperless.ParseMatchingAlgorithm(s)
+			return err
+		},
+	}
+}
+
+func newCaseInsensitiveFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "case-insensitive",
+		Usage:       fmt.Sprintf("set whether --%s is case-insensitive.", newMatchFlag(nil).Name),
+		Destination: dest,
+	}
+}
+
+func newColorFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "color",
+		Usage:       `set the color of the tag as hex value, e.g. "#a6cee3".`,
+		Destination: dest,
+	}
+}
+
+func newInboxTagFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "inbox-tag",
+		Usage:       "set whether the tag is assigned to all newly consumed documents.",
+		Destination: dest,
+	}
+}
+
+func newStoragePathFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "path",
+		Usage:       `set the file name format of the storage path, e.g. "{created_year}/{correspondent}/{title}".`,
+		Destination: dest,
+	}
+}
+
+func newPruneFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "prune",
+		Usage:       "delete entities that are not in the manifest.",
+		Destination: dest,
+	}
+}
+
+func newMetricsAddressFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "metrics-address", EnvVars: []string{"METRICS_ADDRESS"},
+		Usage:       "address to serve Prometheus metrics on /metrics and probes on /healthz and /readyz, e.g. ':9090'. Disabled if empty.",
+		Destination: dest,
+	})
+}
+
+func newTaskTimeoutFlag(dest *time.Duration) *cli.DurationFlag {
+	return &cli.DurationFlag{
+		Name:        "task-timeout",
+		Usage:       "maximum time to wait until an uploaded document has been consumed by Paperless.",
+		Value:       10 * time.Minute,
+		Destination: dest,
+	}
+}
+
+func newFilenameFormatFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "filename-format", EnvVars: []string{"DOWNLOAD_FILENAME_FORMAT"},
+		Usage: fmt.Sprintf("template for the local file paths, e.g. %q. "+
+			"Placeholders: {id}, {title}, {correspondent}, {document_type}, {tag_list}, {created}, {created_year}, {created_month}, {created_day}, {original_name}. "+
+			"If empty, the file names are given by the Paperless instance. Requires --%s",
+			"{correspondent}/{created_year}/{title}-{id}", newIncrementalFlag(nil).Name),
+		Destination: dest,
+	})
+}
+
+func newExportFormatFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "export-format", EnvVars: []string{"DOWNLOAD_EXPORT_FORMAT"},
+		Usage: fmt.Sprintf("write a manifest of all documents, their notes and related entities next to the files, one of %v. "+
+			"If empty, no manifest is written. Requires --%s", export.Formats, newIncrementalFlag(nil).Name),
+		Destination: dest,
+		Action: func(ctx *cli.Context, s string) error {
+			if s == "" {
+				return nil
+			}
+			_, err := export.ParseFormat(s)
+			return err
+		},
+	})
+}
+
+func newMaxDeletionsFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "max-deletions", EnvVars: []string{"DOWNLOAD_MAX_DELETIONS"},
+		Usage: fmt.Sprintf("abort if more local documents would be deleted than the given number or percentage (e.g. \"10%%\"), unless --%s is given.",
+			newForceFlag(nil).Name),
+		Value:       "25%",
+		Destination: dest,
+		Action: func(ctx *cli.Context, s string) error {
+			_, err := safedelete.ParseThreshold(s)
+			return err
+		},
+	})
+}
+
+func newForceFlag(dest *bool) *cli.BoolFlag {
+	return &cli.BoolFlag{
+		Name:        "force",
+		Usage:       "delete local documents even if the threshold of --max-deletions is exceeded.",
+		Destination: dest,
+	}
+}
+
+func newTrashRetentionFlag(dest *time.Duration) *altsrc.DurationFlag {
+	return altsrc.NewDurationFlag(&cli.DurationFlag{
+		Name: "trash-retention", EnvVars: []string{"DOWNLOAD_TRASH_RETENTION"},
+		Usage: fmt.Sprintf("duration for which deleted local files are kept in the %q directory of the target path. Set to 0 to delete files immediately.",
+			safedelete.TrashDirName),
+		Value:       30 * 24 * time.Hour,
+		Destination: dest,
+	})
+}
+
+func newWithContentFlag(dest *bool) *altsrc.BoolFlag {
+	return altsrc.NewBoolFlag(&cli.BoolFlag{
+		Name: "with-content", EnvVars: []string{"DOWNLOAD_WITH_CONTENT"},
+		Usage: fmt.Sprintf("store the full OCR content of each document in the metadata to allow offline search. Only effective with --%s",
+			newIncrementalFlag(nil).Name),
+		Destination: dest,
+	})
+}
+
+func newMirrorDirFlag(dest *string) *altsrc.StringFlag {
+	return altsrc.NewStringFlag(&cli.StringFlag{
+		Name: "target-path", EnvVars: []string{"DOWNLOAD_TARGET_PATH"},
+		Usage:       fmt.Sprintf("directory of the local mirror created by bulk-download with --%s.", newIncrementalFlag(nil).Name),
+		DefaultText: "documents",
+		Destination: dest,
+	})
+}
+
+func newSearchTitleFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "title",
+		Usage:       "only match documents whose title contains the given text.",
+		Destination: dest,
+	}
+}
+
+func newSearchContentFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "content",
+		Usage:       fmt.Sprintf("only match documents whose OCR content contains the given text. Requires the mirror to be downloaded with --%s.", newWithContentFlag(nil).Name),
+		Destination: dest,
+	}
+}
+
+func newSearchTagFlag(dest *cli.StringSlice) *cli.StringSliceFlag {
+	return &cli.StringSliceFlag{
+		Name:        "tag",
+		Usage:       "only match documents that have all the given tag(s) assigned.",
+		Destination: dest,
+	}
+}
+
+func newSearchCorrespondentFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "correspondent",
+		Usage:       "only match documents with the given correspondent.",
+		Destination: dest,
+	}
+}
+
+func newSearchDocumentTypeFlag(dest *string) *cli.StringFlag {
+	return &cli.StringFlag{
+		Name:        "type",
+		Usage:       "only match documents with the given document type.",
+		Destination: dest,
+	}
+}
+
+func newCreatedAfterFlag(dest *cli.Timestamp) *cli.TimestampFlag {
+	return &cli.TimestampFlag{
+		Name:        "created-after",
+		Usage:       "only match documents created on or after the given date.",
+		Layout:      "2006-01-02",
+		Destination: dest,
+	}
+}
+
+func newCreatedBeforeFlag(dest *cli.Timestamp) *cli.TimestampFlag {
+	return &cli.TimestampFlag{
+		Name:        "created-before",
+		Usage:       "only match documents created on or before the given date.",
+		Layout:      "2006-01-02",
+		Destination: dest,
 	}
-	return altsrc.InitInputSourceWithContext(flags, altsrc.NewYamlSourceFromFlagFunc(newConfigFileFlag().Name))(ctx)
 }
 
 func checkEmptyString(flagName string) func(*cli.Context, string) error {
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/taxonomy"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+type ImportCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+
+	TaskTimeout time.Duration
+	DryRun      bool
+}
+
+const importDesc = `Re-uploads the original documents of a local mirror to a Paperless instance, e.g. to migrate to another instance or to test a backup.
+The mirror has to be created with "bulk-download --%s --%s=%s --%s=<format>".
+Missing tags, correspondents, document types, storage paths and custom fields are created by name.
+Once a document has been consumed, its ASN, storage path, custom fields and notes are restored.`
+
+// importIDs contains the IDs of the entities on the server by kind and their ID in the export.
+type importIDs map[taxonomy.Kind]map[int]int
+
+// get returns the ID on the server of the exported entity.
+func (ids importIDs) get(kind taxonomy.Kind, exportedID int) (int, error) {
+	if id, found := ids[kind][exportedID]; found {
+		return id, nil
+	}
+	return 0, fmt.Errorf("%s %d is not contained in the export", kind, exportedID)
+}
+
+// importPollInterval is the interval in which the status of an upload task is checked.
+var importPollInterval = 2 * time.Second
+
+func newImportCommand() *ImportCommand {
+	c := &ImportCommand{}
+	c.Command = cli.Command{
+		Name:  "import",
+		Usage: "Uploads the documents of a local mirror",
+		Description: fmt.Sprintf(importDesc, newIncrementalFlag(nil).Name, newDownloadContentFlag(nil).Name,
+			paperless.BulkDownloadOriginal, newExportFormatFlag(nil).Name),
+		Before:    before(requireArgs, loadConfigFileFn),
+		Action:    actions(LogMetadata, c.Action),
+		ArgsUsage: "DIR",
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+			newTaskTimeoutFlag(&c.TaskTimeout),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *ImportCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	dir := ctx.Args().First()
+
+	manifest, err := export.ReadFile(dir)
+	if err != nil {
+		return err
+	}
+	log.Info("Read manifest", "documents", len(manifest.Documents), "server", manifest.Server, "exported_at", manifest.ExportedAt)
+
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	ids, err := createMissingEntities(ctx, clt, manifest, c.DryRun)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	results := make([]documentResult, 0, len(manifest.Documents))
+	for _, doc := range manifest.Documents {
+		result := documentResult{ID: doc.ID, Title: doc.Title, Action: "imported"}
+		if c.DryRun {
+			log.Info("Would import document", "id", doc.ID, "title", doc.Title, "file", originalFile(doc))
+			results = append(results, result)
+			continue
+		}
+		file := originalFile(doc)
+		if file == "" {
+			fileErr := fmt.Errorf("no downloaded file found")
+			log.Error(fileErr, "Could not import document", "id", doc.ID, "title", doc.Title)
+			results = append(results, result.failed(fileErr))
+			failed++
+			continue
+		}
+		if !strings.HasPrefix(file, paperless.BulkDownloadOriginal.String()+"/") {
+			log.Info("Original file not found, importing archived version instead", "id", doc.ID, "file", file)
+		}
+		newID, importErr := uploadWithMetadata(ctx, clt, filepath.Join(dir, filepath.FromSlash(file)), doc.Document, ids, c.TaskTimeout)
+		if importErr != nil {
+			log.Error(importErr, "Could not import document", "id", doc.ID, "title", doc.Title)
+			results = append(results, result.failed(importErr))
+			failed++
+			continue
+		}
+		log.Info("Imported document", "id", doc.ID, "new_id", newID, "title", doc.Title)
+		result.NewID = newID
+		results = append(results, result)
+	}
+	if printErr := printDocumentResults(ctx, results); printErr != nil {
+		return printErr
+	}
+	if c.DryRun {
+		log.Info("Would import documents", "count", len(manifest.Documents))
+		return nil
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents could not be imported", failed, len(manifest.Documents))
+	}
+	log.Info("Imported documents", "count", len(manifest.Documents))
+	return nil
+}
+
+// createMissingEntities creates the entities of the export that don't exist on the server yet.
+// Existing entities with the same name are left unchanged.
+func createMissingEntities(ctx *cli.Context, clt *paperless.Client, manifest *export.Manifest, dryRun bool) (importIDs, error) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	specs := taxonomy.FromExport(manifest)
+	ids := importIDs{}
+	for _, kind := range taxonomy.Kinds {
+		existing, err := taxonomy.Fetch(ctx.Context, clt, kind)
+		if err != nil {
+			return nil, fmt.Errorf("cannot query %ss: %w", kind, err)
+		}
+		changes, err := taxonomy.Plan(kind, specs.Specs(kind), existing, false)
+		if err != nil {
+			return nil, err
+		}
+		created := 0
+		for _, change := range changes {
+			if change.Action != taxonomy.ActionCreate {
+				continue
+			}
+			created++
+			if dryRun {
+				log.Info("Would create "+string(kind), "name", change.Name)
+				continue
+			}
+			if applyErr := taxonomy.Apply(ctx.Context, clt, change); applyErr != nil {
+				return nil, applyErr
+			}
+			log.V(1).Info("Created "+string(kind), "name", change.Name)
+		}
+		if created > 0 && !dryRun {
+			if existing, err = taxonomy.Fetch(ctx.Context, clt, kind); err != nil {
+				return nil, fmt.Errorf("cannot query %ss: %w", kind, err)
+			}
+		}
+		ids[kind] = mapExportedIDs(exportedNames(manifest, kind), existing)
+	}
+	return ids, nil
+}
+
+// uploadWithMetadata uploads the file of the document, waits until it's consumed and restores the remaining metadata.
+// The IDs of related entities in doc are translated with ids.
+// It returns the ID of the new document.
+func uploadWithMetadata(ctx *cli.Context, clt *paperless.Client, filePath string, doc paperless.Document, ids importIDs, timeout time.Duration) (int, error) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	params := paperless.UploadParams{Title: doc.Title, Created: doc.CreatedDate()}
+	if doc.Correspondent != nil {
+		id, lookupErr := ids.get(taxonomy.KindCorrespondent, *doc.Correspondent)
+		if lookupErr != nil {
+			return 0, lookupErr
+		}
+		params.Correspondent = strconv.Itoa(id)
+	}
+	if doc.DocumentType != nil {
+		id, lookupErr := ids.get(taxonomy.KindDocumentType, *doc.DocumentType)
+		if lookupErr != nil {
+			return 0, lookupErr
+		}
+		params.DocumentType = strconv.Itoa(id)
+	}
+	for _, tag := range doc.Tags {
+		id, lookupErr := ids.get(taxonomy.KindTag, tag)
+		if lookupErr != nil {
+			return 0, lookupErr
+		}
+		params.Tags = append(params.Tags, strconv.Itoa(id))
+	}
+	patch, err := makeImportPatch(doc, ids)
+	if err != nil {
+		return 0, err
+	}
+	log.V(1).Info("Uploading document", "id", doc.ID, "file", filePath)
+	taskID, err := clt.UploadDocument(ctx.Context, filePath, params)
+	if err != nil {
+		return 0, err
+	}
+
+	log.V(1).Info("Waiting for document to be consumed", "id", doc.ID, "task", taskID)
+	waitCtx, cancel := context.WithTimeout(ctx.Context, timeout)
+	defer cancel()
+	task, err := clt.WaitForTask(waitCtx, taskID, importPollInterval)
+	if err != nil {
+		return 0, err
+	}
+	newID, found := task.DocumentID()
+	if !found {
+		return 0, fmt.Errorf("task %s did not create a document: %s", taskID, task.Result)
+	}
+
+	if len(patch) > 0 {
+		if _, err := clt.UpdateDocument(ctx.Context, newID, patch); err != nil {
+			return newID, fmt.Errorf("cannot restore metadata of new document %d: %w", newID, err)
+		}
+	}
+	for _, note := range doc.Notes {
+		if err := clt.AddNote(ctx.Context, newID, note.Note); err != nil {
+			return newID, fmt.Errorf("cannot restore notes of new document %d: %w", newID, err)
+		}
+	}
+	return newID, nil
+}
+
+// makeImportPatch returns the fields of the document that cannot be set when uploading.
+func makeImportPatch(doc paperless.Document, ids importIDs) (paperless.DocumentPatch, error) {
+	patch := paperless.DocumentPatch{}
+	if doc.ArchiveSerialNumber != nil {
+		patch["archive_serial_number"] = *doc.ArchiveSerialNumber
+	}
+	if doc.StoragePath != nil {
+		id, err := ids.get(taxonomy.KindStoragePath, *doc.StoragePath)
+		if err != nil {
+			return nil, err
+		}
+		patch["storage_path"] = id
+	}
+	if len(doc.CustomFields) > 0 {
+		fields := make([]paperless.CustomFieldInstance, len(doc.CustomFields))
+		for i, field := range doc.CustomFields {
+			id, err := ids.get(taxonomy.KindCustomField, field.Field)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = paperless.CustomFieldInstance{Field: id, Value: field.Value}
+		}
+		patch["custom_fields"] = fields
+	}
+	return patch, nil
+}
+
+// originalFile returns the downloaded original file of the document, or the archived version if there's no original.
+func originalFile(doc export.Document) string {
+	for _, file := range doc.Files {
+		if strings.HasPrefix(file, paperless.BulkDownloadOriginal.String()+"/") {
+			return file
+		}
+	}
+	if len(doc.Files) > 0 {
+		return doc.Files[0]
+	}
+	return ""
+}
+
+// exportedNames returns the names of the exported entities of the given kind by their ID.
+func exportedNames(m *export.Manifest, kind taxonomy.Kind) map[int]string {
+	names := map[int]string{}
+	switch kind {
+	case taxonomy.KindTag:
+		for _, e := range m.Tags {
+			names[e.ID] = e.Name
+		}
+	case taxonomy.KindCorrespondent:
+		for _, e := range m.Correspondents {
+			names[e.ID] = e.Name
+		}
+	case taxonomy.KindDocumentType:
+		for _, e := range m.DocumentTypes {
+			names[e.ID] = e.Name
+		}
+	case taxonomy.KindStoragePath:
+		for _, e := range m.StoragePaths {
+			names[e.ID] = e.Name
+		}
+	case taxonomy.KindCustomField:
+		for _, e := range m.CustomFields {
+			names[e.ID] = e.Name
+		}
+	}
+	return names
+}
+
+// mapExportedIDs returns the IDs of the existing entities by the ID of the exported entity with the same name.
+func mapExportedIDs(names map[int]string, existing []taxonomy.Object) map[int]int {
+	byName := map[string]int{}
+	for _, obj := range existing {
+		byName[strings.ToLower(obj.Name())] = obj.ID
+	}
+	ids := map[int]int{}
+	for id, name := range names {
+		if newID, found := byName[strings.ToLower(name)]; found {
+			ids[id] = newID
+		}
+	}
+	return ids
+}
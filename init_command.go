@@ -43,7 +43,7 @@ func (c *InitCommand) Action(ctx *cli.Context) error {
 	}
 
 	if configFilePath == "-" {
-		fmt.Println(string(b))
+		fmt.Fprintln(resultWriter, string(b))
 		return nil
 	}
 	if _, statErr := os.Stat(configFilePath); statErr != nil && os.IsNotExist(statErr) {
@@ -65,6 +65,12 @@ func getValueFor(flag cli.Flag) any {
 	if f, ok := flag.(*altsrc.IntFlag); ok {
 		return f.Value
 	}
+	if f, ok := flag.(*altsrc.StringSliceFlag); ok {
+		if f.Value == nil {
+			return []string{}
+		}
+		return f.Value.Value()
+	}
 	panic(fmt.Errorf("unknown flag type: %v", flag))
 }
 
@@ -75,16 +81,21 @@ func allAltSrcFlags(ctx *cli.Context) []cli.Flag {
 			flagMap[flag.Names()[0]] = f
 		}
 	}
-	for _, subcommand := range ctx.App.Commands {
+	collectAltSrcFlags(ctx.App.Commands, flagMap)
+	flags := make([]cli.Flag, 0)
+	for _, flag := range flagMap {
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+func collectAltSrcFlags(commands []*cli.Command, flagMap map[string]cli.Flag) {
+	for _, subcommand := range commands {
 		for _, flag := range subcommand.Flags {
 			if f, isAltSrcFlag := flag.(altsrc.FlagInputSourceExtension); isAltSrcFlag {
 				flagMap[flag.Names()[0]] = f
 			}
 		}
+		collectAltSrcFlags(subcommand.Subcommands, flagMap)
 	}
-	flags := make([]cli.Flag, 0)
-	for _, flag := range flagMap {
-		flags = append(flags, flag)
-	}
-	return flags
 }
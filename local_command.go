@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+type LocalCommand struct {
+	cli.Command
+}
+
+func newLocalCommand() *LocalCommand {
+	c := &LocalCommand{}
+	c.Command = cli.Command{
+		Name:  "local",
+		Usage: "Works with the local mirror created by bulk-download, without connecting to the Paperless instance",
+		Subcommands: []*cli.Command{
+			&newLocalSearchCommand().Command,
+		},
+	}
+	return c
+}
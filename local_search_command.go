@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ccremer/paperless-cli/pkg/localdb"
+	"github.com/go-logr/logr"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+type LocalSearchCommand struct {
+	cli.Command
+
+	TargetPath    string
+	Title         string
+	Content       string
+	Tags          cli.StringSlice
+	Correspondent string
+	DocumentType  string
+	CreatedAfter  cli.Timestamp
+	CreatedBefore cli.Timestamp
+}
+
+const localSearchDesc = `Searches the metadata of the local mirror and prints the paths of the matching files.
+If TEXT is given, it has to be contained in either the title or the OCR content of the document.
+The mirror has to be downloaded with "bulk-download --%s" first.`
+
+func newLocalSearchCommand() *LocalSearchCommand {
+	c := &LocalSearchCommand{}
+	c.Command = cli.Command{
+		Name:        "search",
+		Usage:       "Searches documents in the local mirror",
+		Description: fmt.Sprintf(localSearchDesc, newIncrementalFlag(nil).Name),
+		Before:      loadConfigFileFn,
+		Action:      c.Action,
+		Flags: []cli.Flag{
+			newMirrorDirFlag(&c.TargetPath),
+			newSearchTitleFlag(&c.Title),
+			newSearchContentFlag(&c.Content),
+			newSearchTagFlag(&c.Tags),
+			newSearchCorrespondentFlag(&c.Correspondent),
+			newSearchDocumentTypeFlag(&c.DocumentType),
+			newCreatedAfterFlag(&c.CreatedAfter),
+			newCreatedBeforeFlag(&c.CreatedBefore),
+		},
+		ArgsUsage: "[TEXT]",
+	}
+	return c
+}
+
+func (c *LocalSearchCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	dir := c.getTargetPath()
+
+	log.V(1).Info("Opening DB", "dir", dir)
+	db, openErr := localdb.Open(dir)
+	if openErr != nil {
+		return openErr
+	}
+
+	query := localdb.SearchQuery{
+		Text:          ctx.Args().First(),
+		Title:         c.Title,
+		Content:       c.Content,
+		Tags:          c.Tags.Value(),
+		Correspondent: c.Correspondent,
+		DocumentType:  c.DocumentType,
+	}
+	if after := c.CreatedAfter.Value(); after != nil {
+		query.CreatedAfter = *after
+	}
+	if before := c.CreatedBefore.Value(); before != nil {
+		query.CreatedBefore = *before
+	}
+	documents := db.Search(query)
+	log.V(1).Info("Found matching documents", "count", len(documents))
+
+	results := make([]searchResult, 0, len(documents))
+	for _, doc := range documents {
+		paths := db.GetFiles(doc.ID)
+		if len(paths) == 0 {
+			log.V(1).Info("No local file found for document", "id", doc.ID, "title", doc.Title)
+		}
+		result := searchResult{ID: doc.ID, Title: doc.Title, Created: doc.Created, Files: make([]string, len(paths))}
+		for i, path := range paths {
+			result.Files[i] = filepath.Join(dir, filepath.FromSlash(path))
+		}
+		results = append(results, result)
+	}
+	return printResult(ctx, resultPrinter{
+		result: results,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"ID", "Title", "Created", "File"}}
+			for _, r := range results {
+				for _, file := range r.Files {
+					data = append(data, []string{strconv.Itoa(r.ID), r.Title, r.Created, file})
+				}
+			}
+			return data
+		},
+		text: func(w io.Writer) error {
+			for _, r := range results {
+				for _, file := range r.Files {
+					if _, err := fmt.Fprintln(w, file); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	})
+}
+
+// searchResult is a document found in the local mirror.
+type searchResult struct {
+	ID      int      `json:"id"`
+	Title   string   `json:"title"`
+	Created string   `json:"created,omitempty"`
+	Files   []string `json:"files"`
+}
+
+func (c *LocalSearchCommand) getTargetPath() string {
+	if c.TargetPath != "" {
+		return c.TargetPath
+	}
+	return "documents"
+}
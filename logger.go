@@ -1,16 +1,29 @@
 package main
 
 import (
+	"log/slog"
 	"os"
 	"runtime"
 
 	"github.com/ccremer/plogr"
 	"github.com/go-logr/logr"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
 )
 
+const (
+	logFormatConsole = "console"
+	logFormatJSON    = "json"
+	logFormatLogfmt  = "logfmt"
+)
+
+var logFormats = []string{logFormatConsole, logFormatJSON, logFormatLogfmt}
+
 var logger logr.Logger
 
+// structuredLogs is true if the logs are written as JSON or logfmt instead of the human-readable console format.
+var structuredLogs bool
+
 func init() {
 	// Remove `-v` short option from --version flag
 	cli.VersionFlag.(*cli.BoolFlag).Aliases = nil
@@ -34,12 +47,57 @@ func LogMetadata(c *cli.Context) error {
 }
 
 func setupLogging(c *cli.Context) error {
-	sink := newSink(c.Int(newLogLevelFlag().Name))
-	logger = logr.New(sink)
+	level := c.Int(newLogLevelFlag().Name)
+	format := c.String(newLogFormatFlag().Name)
+	if format == "" {
+		format = detectLogFormat()
+	}
+	switch format {
+	case logFormatJSON:
+		logger = logr.FromSlogHandler(slog.NewJSONHandler(os.Stderr, newSlogOptions(level)))
+		structuredLogs = true
+	case logFormatLogfmt:
+		logger = logr.FromSlogHandler(slog.NewTextHandler(os.Stderr, newSlogOptions(level)))
+		structuredLogs = true
+	default:
+		logger = logr.New(newSink(level))
+	}
 	c.Context = logr.NewContext(c.Context, logger)
 	return nil
 }
 
+// detectLogFormat returns the console format if stderr is a terminal and JSON otherwise, e.g. in journald or a container.
+func detectLogFormat() string {
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		return logFormatConsole
+	}
+	return logFormatJSON
+}
+
+// newSlogOptions enables the given verbosity and names the levels of verbose logs "DEBUG".
+// logr maps the verbosity V(n) to the slog level -n.
+func newSlogOptions(level int) *slog.HandlerOptions {
+	return &slog.HandlerOptions{
+		Level: slog.Level(-level),
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if lvl, ok := attr.Value.Any().(slog.Level); ok && attr.Key == slog.LevelKey && lvl < slog.LevelInfo {
+				attr.Value = slog.StringValue(slog.LevelDebug.String())
+			}
+			return attr
+		},
+	}
+}
+
+// printError prints the error that terminates the app.
+// With structured logs, the error is logged instead, so that it can be parsed by log aggregators.
+func printError(err error) {
+	if structuredLogs {
+		logger.Error(err, "Command failed")
+		return
+	}
+	plogr.DefaultErrorPrinter.Println(err.Error())
+}
+
 func newSink(level int) *plogr.PtermSink {
 	sink := plogr.NewPtermSink()
 	sink.ErrorPrinter.ShowLineNumber = true
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/credentials"
+	"github.com/go-logr/logr"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+)
+
+const (
+	storeKeyring = "keyring"
+	storeConfig  = "config"
+)
+
+type LoginCommand struct {
+	cli.Command
+
+	PaperlessURL  string
+	PaperlessUser string
+	WithToken     bool
+	Store         string
+}
+
+func newLoginCommand() *LoginCommand {
+	c := &LoginCommand{}
+	c.Command = cli.Command{
+		Name:  "login",
+		Usage: "Obtains an API token of a paperless instance and stores it",
+		Description: `Prompts for the URL, username and password, if not given by flags, and exchanges them for an API token.
+The password is read with hidden input, or from stdin if it isn't a terminal.
+The token is verified and stored in the OS keyring or in the config file, depending on --store.
+Other commands use the token from the keyring if --token, --token-file and --token-command are empty.
+
+With --with-token, an existing token is read instead of the password.`,
+		Before: ignoreMissingProfile(loadConfigFileFn),
+		Action: c.Action,
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newWithTokenFlag(&c.WithToken),
+			newCredentialStoreFlag(&c.Store),
+		},
+	}
+	return c
+}
+
+func (c *LoginCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	url, err := promptIfEmpty(ctx, c.PaperlessURL, newURLFlag(nil).Name, "URL")
+	if err != nil {
+		return err
+	}
+	url = strings.TrimSuffix(url, "/")
+	// both clients share the bearer token, if a proxy requires one
+	clients := &clientFactory{}
+	clt, err := clients.newClient(ctx, url, "", "")
+	if err != nil {
+		return err
+	}
+
+	var token string
+	if c.WithToken {
+		token, err = readSecret("Token")
+		if err != nil {
+			return err
+		}
+	} else {
+		username, err := promptIfEmpty(ctx, c.PaperlessUser, newUsernameFlag(nil).Name, "Username")
+		if err != nil {
+			return err
+		}
+		password, err := readSecret("Password")
+		if err != nil {
+			return err
+		}
+		token, err = clt.ObtainToken(ctx.Context, username, password)
+		if err != nil {
+			return err
+		}
+	}
+
+	clt, err = clients.newClient(ctx, url, "", token)
+	if err != nil {
+		return err
+	}
+	user, err := clt.GetCurrentUser(ctx.Context)
+	if err != nil {
+		return fmt.Errorf("cannot verify token: %w", err)
+	}
+	log.Info("Logged in", "url", url, "username", user.Username)
+
+	if c.Store == storeConfig {
+		path := ctx.String(newConfigFileFlag().Name)
+		profile := ctx.String(newProfileFlag().Name)
+		// the token replaces the password of BasicAuth
+		if err := saveConfigValues(path, profile, map[string]string{"url": url, "token": token}, newUsernameFlag(nil).Name); err != nil {
+			return err
+		}
+		log.Info("Stored token in config file", "path", path, "profile", profile)
+		return nil
+	}
+	if err := credentials.StoreInKeyring(url, "", token); err != nil {
+		return err
+	}
+	log.Info("Stored token in keyring")
+	return nil
+}
+
+// promptIfEmpty returns value, or asks for it if it's empty and the input is a terminal.
+func promptIfEmpty(ctx *cli.Context, value, flagName, prompt string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", showFlagError(ctx, fmt.Errorf(`Required flag %q not set`, flagName))
+	}
+	input, err := pterm.DefaultInteractiveTextInput.Show(prompt)
+	if err != nil {
+		return "", fmt.Errorf("cannot read %s: %w", strings.ToLower(prompt), err)
+	}
+	if input = strings.TrimSpace(input); input == "" {
+		return "", fmt.Errorf("%s is empty", strings.ToLower(prompt))
+	}
+	return input, nil
+}
+
+// readSecret reads a password or token from the terminal with hidden input, or the first line of stdin if it isn't a terminal.
+func readSecret(prompt string) (string, error) {
+	var secret string
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		input, err := pterm.DefaultInteractiveTextInput.WithMask("*").Show(prompt)
+		if err != nil {
+			return "", fmt.Errorf("cannot read %s: %w", strings.ToLower(prompt), err)
+		}
+		secret = input
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("cannot read %s from stdin: %w", strings.ToLower(prompt), err)
+		}
+		secret = line
+	}
+	secret = strings.TrimRight(secret, "\r\n")
+	if strings.TrimSpace(secret) == "" {
+		return "", fmt.Errorf("%s is empty", strings.ToLower(prompt))
+	}
+	return secret, nil
+}
@@ -5,7 +5,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/ccremer/plogr"
 	"github.com/urfave/cli/v2"
 )
 
@@ -26,7 +25,7 @@ func main() {
 	app := NewApp()
 	err := app.Run(os.Args)
 	if err != nil {
-		plogr.DefaultErrorPrinter.Println(err.Error())
+		printError(err)
 		os.Exit(1)
 	}
 }
@@ -37,18 +36,57 @@ func NewApp() *cli.App {
 		Usage:   appLongName,
 		Version: fmt.Sprintf("%s, revision=%s, date=%s", version, commit, date),
 
-		Before: before(loadConfigFileFn, setupLogging),
+		// the profile is validated by the subcommands, e.g. "login" creates it.
+		Before: before(setupOutput, ignoreMissingProfile(loadConfigFileFn), setupLogging),
 		Flags: []cli.Flag{
 			newLogLevelFlag(),
+			newLogFormatFlag(),
+			newOutputFlag(),
 			newConfigFileFlag(),
+			newProfileFlag(),
+			newTokenFileFlag(),
+			newTokenCommandFlag(),
+			newCAFileFlag(),
+			newClientCertFlag(),
+			newClientKeyFlag(),
+			newTLSMinVersionFlag(),
+			newInsecureSkipVerifyFlag(),
+			newProxyFlag(),
+			newConnectTimeoutFlag(),
+			newRequestTimeoutFlag(),
+			newReadTimeoutFlag(),
+			newIdleTimeoutFlag(),
+			newHeaderFlag(),
+			newBearerTokenFlag(),
+			newOIDCIssuerFlag(),
+			newOIDCClientIDFlag(),
+			newOIDCClientSecretFlag(),
+			newOIDCScopeFlag(),
+			newOIDCFlowFlag(),
 		},
 		Commands: []*cli.Command{
 			&newUploadCommand().Command,
 			&newBulkDownloadCommand().Command,
+			&newBulkEditCommand().Command,
+			&newApplyCommand().Command,
+			&newImportCommand().Command,
+			&newSyncCommand().Command,
 			&newConsumeCommand().Command,
 			&newInitCommand().Command,
+			&newLoginCommand().Command,
+			&newStatusCommand().Command,
+			&newCompletionCommand().Command,
+			&newLocalCommand().Command,
+			&newDocumentCommand().Command,
+			&newEntityCommand(tagKind).Command,
+			&newEntityCommand(correspondentKind).Command,
+			&newEntityCommand(documentTypeKind).Command,
+			&newEntityCommand(storagePathKind).Command,
 		},
+		EnableBashCompletion: true,
+		BashComplete:         completeCommand,
 	}
+	enableCompletion(app.Commands)
 	return app
 }
 
@@ -77,6 +115,14 @@ func before(actions ...cli.BeforeFunc) cli.BeforeFunc {
 	}
 }
 
+// requireArgs shows the usage of the command and returns an error if no arguments are given.
+func requireArgs(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return showFlagError(ctx, fmt.Errorf("At least one argument is required"))
+	}
+	return nil
+}
+
 func actions(actions ...cli.ActionFunc) cli.ActionFunc {
 	return func(ctx *cli.Context) error {
 		for _, action := range actions {
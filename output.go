@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputText  = "text"
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputText, outputTable, outputJSON, outputYAML}
+
+// resultWriter is where the results of commands are written to.
+// Logs, prompts and progress messages are written to stderr instead, see setupOutput.
+var resultWriter io.Writer = os.Stdout
+
+// setupOutput writes all output of pterm to stderr, so that only the results of commands are written to stdout.
+func setupOutput(_ *cli.Context) error {
+	pterm.SetDefaultOutput(os.Stderr)
+	return nil
+}
+
+// resultPrinter renders the result of a command for the different output formats.
+type resultPrinter struct {
+	// result is serialized as is for the JSON and YAML output.
+	result any
+	// table returns the rows of the result including header for the table output.
+	table func() pterm.TableData
+	// text writes the result in a human-readable form for the text output.
+	// The table is written instead if nil.
+	// Nothing is written if both are nil, e.g. if the logs already tell what has been done.
+	text func(w io.Writer) error
+}
+
+// printResult writes the result to stdout in the format given by the global --output flag.
+func printResult(ctx *cli.Context, p resultPrinter) error {
+	switch ctx.String(newOutputFlag().Name) {
+	case outputJSON:
+		enc := json.NewEncoder(resultWriter)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p.result)
+	case outputYAML:
+		return writeYAML(resultWriter, p.result)
+	case outputTable:
+		return writeTable(resultWriter, p.table)
+	}
+	if p.text != nil {
+		return p.text(resultWriter)
+	}
+	return writeTable(resultWriter, p.table)
+}
+
+// isStructuredOutput returns true if the results are serialized to JSON or YAML.
+func isStructuredOutput(ctx *cli.Context) bool {
+	format := ctx.String(newOutputFlag().Name)
+	return format == outputJSON || format == outputYAML
+}
+
+// writeTable writes the table, without colors if stdout isn't a terminal.
+func writeTable(w io.Writer, table func() pterm.TableData) error {
+	if table == nil {
+		return nil
+	}
+	rendered, err := pterm.DefaultTable.WithHasHeader().WithData(table()).Srender()
+	if err != nil {
+		return err
+	}
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		rendered = pterm.RemoveColorFromString(rendered)
+	}
+	_, err = fmt.Fprint(w, rendered)
+	return err
+}
+
+// writeYAML writes the value as YAML with the field names of the JSON tags.
+func writeYAML(w io.Writer, value any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot serialize result: %w", err)
+	}
+	var generic any
+	if err := json.Unmarshal(b, &generic); err != nil {
+		return fmt.Errorf("cannot serialize result: %w", err)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return fmt.Errorf("cannot serialize result: %w", err)
+	}
+	return enc.Close()
+}
@@ -13,25 +13,27 @@ import (
 )
 
 // Unzip reads and copies every file in the archive to the destination dir.
-func Unzip(ctx context.Context, source, dest string) error {
+// It returns the slash-separated paths of the extracted files relative to the destination dir.
+func Unzip(ctx context.Context, source, dest string) ([]string, error) {
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(1).Info("Unzipping file", "source", source, "dest", dest)
 	archive, openErr := zip.OpenReader(source)
 	if openErr != nil {
-		return fmt.Errorf("cannot open source file: %w", openErr)
+		return nil, fmt.Errorf("cannot open source file: %w", openErr)
 	}
 	defer archive.Close()
 
+	files := make([]string, 0, len(archive.File))
 	for _, f := range archive.File {
 		destFilePath := filepath.Join(dest, f.Name)
 
 		if !strings.HasPrefix(destFilePath, filepath.Clean(dest)+string(os.PathSeparator)) {
-			return fmt.Errorf("invalid file path: %s", destFilePath)
+			return nil, fmt.Errorf("invalid file path: %s", destFilePath)
 		}
 		if f.FileInfo().IsDir() {
 			log.V(2).Info("Creating directory", "dir", f.FileInfo().Name())
 			if mkdirErr := os.MkdirAll(destFilePath, os.ModePerm); mkdirErr != nil {
-				return fmt.Errorf("cannot create directory: %w", mkdirErr)
+				return nil, fmt.Errorf("cannot create directory: %w", mkdirErr)
 			}
 			continue
 		}
@@ -39,10 +41,11 @@ func Unzip(ctx context.Context, source, dest string) error {
 
 		err := unzipFile(f, destFilePath)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		files = append(files, f.Name)
 	}
-	return nil
+	return files, nil
 }
 
 func unzipFile(f *zip.File, destFilePath string) error {
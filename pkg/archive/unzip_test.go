@@ -17,8 +17,9 @@ func TestUnzip(t *testing.T) {
 	// cleanup previous test files in case of failure
 	require.NoError(t, os.RemoveAll(testDir))
 
-	err := Unzip(context.TODO(), testFilePath, testDir)
+	files, err := Unzip(context.TODO(), testFilePath, testDir)
 	assert.NoError(t, err, "unzip failed with error")
+	assert.ElementsMatch(t, []string{"toplevel.file", "Dir In Archive/Sub Dir.file"}, files)
 
 	assert.FileExists(t, filepath.Join(testDir, "toplevel.file"))
 	assert.FileExists(t, filepath.Join(testDir, "Dir In Archive", "Sub Dir.file"))
@@ -0,0 +1,61 @@
+package completion
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache stores the values for shell completion in files, so that the server isn't queried on each key press.
+// The cache is best effort: values are returned even if they cannot be written.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewCache returns a cache in the given dir whose entries expire after the given duration.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// Get returns the cached values of the key.
+// If they are missing or expired, the values are fetched and cached.
+func (c *Cache) Get(key string, fetch func() ([]string, error)) ([]string, error) {
+	path := c.path(key)
+	if values, ok := c.read(path); ok {
+		return values, nil
+	}
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	if b, marshalErr := json.Marshal(values); marshalErr == nil && os.MkdirAll(c.dir, 0700) == nil {
+		_ = os.WriteFile(path, b, 0600)
+	}
+	return values, nil
+}
+
+func (c *Cache) read(path string) ([]string, bool) {
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	values := make([]string, 0)
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, false
+	}
+	return values, true
+}
+
+// path returns the file of the key, which may contain characters that aren't allowed in file names, e.g. a URL.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
@@ -0,0 +1,67 @@
+package completion
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Get(t *testing.T) {
+	tests := map[string]struct {
+		givenCached    []string
+		givenAge       time.Duration
+		expectedValues []string
+		expectedFetch  bool
+	}{
+		"Missing": {
+			expectedValues: []string{"Invoice", "Receipt"},
+			expectedFetch:  true,
+		},
+		"Cached": {
+			givenCached:    []string{"Cached"},
+			givenAge:       time.Minute,
+			expectedValues: []string{"Cached"},
+		},
+		"Expired": {
+			givenCached:    []string{"Cached"},
+			givenAge:       time.Hour,
+			expectedValues: []string{"Invoice", "Receipt"},
+			expectedFetch:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := NewCache(t.TempDir(), 10*time.Minute)
+			key := "https://paperless.example/tag"
+			if tt.givenCached != nil {
+				_, err := cache.Get(key, func() ([]string, error) { return tt.givenCached, nil })
+				require.NoError(t, err)
+				modTime := time.Now().Add(-tt.givenAge)
+				require.NoError(t, os.Chtimes(cache.path(key), modTime, modTime))
+			}
+
+			fetched := false
+			values, err := cache.Get(key, func() ([]string, error) {
+				fetched = true
+				return []string{"Invoice", "Receipt"}, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedValues, values)
+			assert.Equal(t, tt.expectedFetch, fetched)
+		})
+	}
+}
+
+func TestCache_Get_FetchError(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Minute)
+	_, err := cache.Get("key", func() ([]string, error) { return nil, errors.New("unreachable") })
+	assert.EqualError(t, err, "unreachable")
+
+	values, err := cache.Get("key", func() ([]string, error) { return []string{"Invoice"}, nil })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Invoice"}, values, "errors must not be cached")
+}
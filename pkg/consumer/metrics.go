@@ -0,0 +1,104 @@
+package consumer
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "paperless_consume"
+
+// Upload results used as label values.
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metrics are the Prometheus metrics about consuming a directory.
+type Metrics struct {
+	filesDetected  prometheus.Counter
+	uploads        *prometheus.CounterVec
+	uploadDuration prometheus.Histogram
+	uploadSize     prometheus.Histogram
+	retries        prometheus.Counter
+	lastSuccess    prometheus.Gauge
+
+	// failedFiles are the files whose last upload failed, which are counted as retry if they are detected again.
+	failedFiles map[string]struct{}
+	mutex       sync.Mutex
+}
+
+// NewMetrics creates the metrics and registers them.
+// The queue depth is read from the given function on each scrape.
+func NewMetrics(reg prometheus.Registerer, queueDepth func() int) *Metrics {
+	m := &Metrics{
+		filesDetected: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Name: "files_detected_total",
+			Help: "Number of files detected in the consumption dir.",
+		}),
+		uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Name: "uploads_total",
+			Help: "Number of upload attempts by result.",
+		}, []string{"result"}),
+		uploadDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace, Name: "upload_duration_seconds",
+			Help:    "Duration of upload attempts.",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+		}),
+		uploadSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: metricsNamespace, Name: "upload_size_bytes",
+			Help:    "Size of the uploaded files.",
+			Buckets: prometheus.ExponentialBuckets(16*1024, 4, 8),
+		}),
+		retries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Name: "upload_retries_total",
+			Help: "Number of files that are queued again after a failed upload.",
+		}),
+		lastSuccess: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Name: "last_success_timestamp_seconds",
+			Help: "Unix time of the last successful upload.",
+		}),
+		failedFiles: map[string]struct{}{},
+	}
+	queueDepthGauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: metricsNamespace, Name: "queue_depth",
+		Help: "Number of files waiting to be uploaded.",
+	}, func() float64 { return float64(queueDepth()) })
+
+	// initialize the label values, so that the series exist before the first upload
+	m.uploads.WithLabelValues(ResultSuccess)
+	m.uploads.WithLabelValues(ResultFailure)
+
+	reg.MustRegister(m.filesDetected, m.uploads, m.uploadDuration, m.uploadSize, m.retries, m.lastSuccess, queueDepthGauge)
+	return m
+}
+
+// FileDetected counts a new file in the consumption dir.
+// If the upload of the file has failed before, e.g. as it has been modified after the failure, it's counted as retry.
+func (m *Metrics) FileDetected(file string) {
+	m.filesDetected.Inc()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, failed := m.failedFiles[file]; failed {
+		delete(m.failedFiles, file)
+		m.retries.Inc()
+	}
+}
+
+// ObserveUpload records an upload attempt of the file that started at the given time.
+// The size is only recorded for successful uploads.
+func (m *Metrics) ObserveUpload(file string, start time.Time, size int64, err error) {
+	m.uploadDuration.Observe(time.Since(start).Seconds())
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err != nil {
+		m.uploads.WithLabelValues(ResultFailure).Inc()
+		m.failedFiles[file] = struct{}{}
+		return
+	}
+	delete(m.failedFiles, file)
+	m.uploads.WithLabelValues(ResultSuccess).Inc()
+	m.uploadSize.Observe(float64(size))
+	m.lastSuccess.SetToCurrentTime()
+}
@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_ObserveUpload(t *testing.T) {
+	tests := map[string]struct {
+		givenErrors         []error
+		expectedSuccess     float64
+		expectedFailure     float64
+		expectedLastSuccess bool
+	}{
+		"Success": {
+			givenErrors:         []error{nil},
+			expectedSuccess:     1,
+			expectedLastSuccess: true,
+		},
+		"Failure": {
+			givenErrors:     []error{errors.New("unreachable")},
+			expectedFailure: 1,
+		},
+		"SuccessAfterFailure": {
+			givenErrors:         []error{errors.New("unreachable"), nil},
+			expectedSuccess:     1,
+			expectedFailure:     1,
+			expectedLastSuccess: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics(prometheus.NewRegistry(), func() int { return 0 })
+			for _, err := range tt.givenErrors {
+				m.ObserveUpload("scan.pdf", time.Now(), 1024, err)
+			}
+			assert.Equal(t, tt.expectedSuccess, testutil.ToFloat64(m.uploads.WithLabelValues(ResultSuccess)))
+			assert.Equal(t, tt.expectedFailure, testutil.ToFloat64(m.uploads.WithLabelValues(ResultFailure)))
+			assert.Equal(t, tt.expectedLastSuccess, testutil.ToFloat64(m.lastSuccess) > 0)
+		})
+	}
+}
+
+func TestMetrics_Retries(t *testing.T) {
+	tests := map[string]struct {
+		givenErrors     []error
+		expectedRetries float64
+	}{
+		"Success": {
+			givenErrors: []error{nil},
+		},
+		"DetectedAgainAfterFailure": {
+			givenErrors:     []error{errors.New("unreachable"), nil},
+			expectedRetries: 1,
+		},
+		"FailedTwice": {
+			givenErrors:     []error{errors.New("unreachable"), errors.New("unreachable"), nil},
+			expectedRetries: 2,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics(prometheus.NewRegistry(), func() int { return 0 })
+			for _, err := range tt.givenErrors {
+				m.FileDetected("scan.pdf")
+				m.ObserveUpload("scan.pdf", time.Now(), 1024, err)
+			}
+			// another file doesn't count as retry
+			m.FileDetected("other.pdf")
+			assert.Equal(t, tt.expectedRetries, testutil.ToFloat64(m.retries))
+			assert.Equal(t, float64(len(tt.givenErrors)+1), testutil.ToFloat64(m.filesDetected))
+		})
+	}
+}
@@ -3,11 +3,13 @@ package consumer
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 )
 
 type Queue[T any] struct {
-	m  sync.Map
-	ch chan T
+	m   sync.Map
+	ch  chan T
+	len atomic.Int64
 }
 
 func NewQueue[T any]() *Queue[T] {
@@ -19,10 +21,16 @@ func NewQueue[T any]() *Queue[T] {
 func (q *Queue[T]) Put(v T) {
 	_, loaded := q.m.LoadOrStore(v, nil)
 	if !loaded {
+		q.len.Add(1)
 		q.ch <- v
 	}
 }
 
+// Len returns the number of values that are waiting to be processed by the subscriber.
+func (q *Queue[T]) Len() int {
+	return int(q.len.Load())
+}
+
 func (q *Queue[T]) Subscribe(ctx context.Context, fn func(v T)) {
 	go func() {
 		for {
@@ -31,6 +39,7 @@ func (q *Queue[T]) Subscribe(ctx context.Context, fn func(v T)) {
 				break
 			case v := <-q.ch:
 				q.m.Delete(v)
+				q.len.Add(-1)
 				fn(v)
 			}
 		}
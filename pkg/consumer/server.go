@@ -0,0 +1,56 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewHandler returns the HTTP handler serving the metrics on /metrics and the probes on /healthz and /readyz.
+// /healthz succeeds as long as the process responds, /readyz only if ready returns true.
+func NewHandler(gatherer prometheus.Gatherer, ready func() bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if !ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
+
+// StartServer serves the handler on the given address in the background until the context is done.
+// It returns an error if it cannot listen on the address, e.g. if it's in use.
+func StartServer(ctx context.Context, addr string, handler http.Handler) error {
+	log := logr.FromContextOrDiscard(ctx)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("cannot listen on %q: %w", addr, err)
+	}
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if serveErr := srv.Serve(listener); !errors.Is(serveErr, http.ErrServerClosed) {
+			log.Error(serveErr, "Metrics server stopped")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	log.Info("Serving metrics", "address", listener.Addr().String())
+	return nil
+}
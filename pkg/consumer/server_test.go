@@ -0,0 +1,66 @@
+package consumer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHandler(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewMetrics(registry, func() int { return 3 })
+	m.FileDetected("scan.pdf")
+
+	tests := map[string]struct {
+		givenPath      string
+		givenReady     bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		"Metrics": {
+			givenPath:      "/metrics",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "paperless_consume_queue_depth 3\n",
+		},
+		"FilesDetected": {
+			givenPath:      "/metrics",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "paperless_consume_files_detected_total 1\n",
+		},
+		"Healthy": {
+			givenPath:      "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"Ready": {
+			givenPath:      "/readyz",
+			givenReady:     true,
+			expectedStatus: http.StatusOK,
+			expectedBody:   "ok",
+		},
+		"NotReady": {
+			givenPath:      "/readyz",
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "not ready",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(NewHandler(registry, func() bool { return tt.givenReady }))
+			defer srv.Close()
+
+			resp, err := http.Get(srv.URL + tt.givenPath)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Contains(t, string(body), tt.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+)
+
+// KeyringService is the name under which the tokens are stored in the OS keyring.
+const KeyringService = "paperless-cli"
+
+// ErrNotFound is returned if no token is stored in the keyring for the given instance.
+var ErrNotFound = errors.New("no token found in keyring")
+
+// FromFile reads the token from the given file, e.g. a Docker or systemd secret.
+// Leading and trailing whitespace, like a final newline, is removed.
+func FromFile(path string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", path)
+	}
+	return token, nil
+}
+
+// FromCommand runs the given shell command and returns its output as token, e.g. "pass show paperless".
+// Leading and trailing whitespace is removed.
+func FromCommand(ctx context.Context, command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("token command returned an empty token")
+	}
+	return token, nil
+}
+
+// FromKeyring returns the token of the given instance from the OS keyring,
+// e.g. the freedesktop Secret Service on Linux.
+// It returns ErrNotFound if there's no token, or if the keyring isn't available.
+func FromKeyring(url, username string) (string, error) {
+	token, err := keyring.Get(KeyringService, keyringUser(url, username))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return token, nil
+}
+
+// LookupKeyring returns the secret of the given instance from the OS keyring.
+// If a username is given, the password stored for that user is preferred.
+// Otherwise, the API token stored for the instance is returned, e.g. by the "login" command,
+// in which case apiToken is true and the username must not be used for authentication.
+// It returns ErrNotFound if there's neither a password nor a token.
+func LookupKeyring(url, username string) (secret string, apiToken bool, err error) {
+	if username != "" {
+		if password, passwordErr := FromKeyring(url, username); passwordErr == nil {
+			return password, false, nil
+		}
+	}
+	token, err := FromKeyring(url, "")
+	if err != nil {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// StoreInKeyring saves the token of the given instance in the OS keyring.
+func StoreInKeyring(url, username, token string) error {
+	if err := keyring.Set(KeyringService, keyringUser(url, username), token); err != nil {
+		return fmt.Errorf("cannot store token in keyring: %w", err)
+	}
+	return nil
+}
+
+// keyringUser returns the key of the token in the keyring.
+// Tokens are stored per instance URL and, if given, per username.
+func keyringUser(url, username string) string {
+	url = strings.TrimSuffix(url, "/")
+	if username == "" {
+		return url
+	}
+	return username + "@" + url
+}
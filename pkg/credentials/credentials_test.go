@@ -0,0 +1,134 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+func TestFromFile(t *testing.T) {
+	tests := map[string]struct {
+		givenContent  string
+		expectedToken string
+		expectedError string
+	}{
+		"TrailingNewline": {
+			givenContent:  "secret\n",
+			expectedToken: "secret",
+		},
+		"Empty": {
+			givenContent:  " \n",
+			expectedError: "token file %s is empty",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "token")
+			require.NoError(t, os.WriteFile(path, []byte(tt.givenContent), 0600))
+			result, err := FromFile(path)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, fmt.Sprintf(tt.expectedError, path))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, result)
+		})
+	}
+}
+
+func TestFromCommand(t *testing.T) {
+	tests := map[string]struct {
+		givenCommand  string
+		expectedToken string
+		expectedError string
+	}{
+		"Output": {
+			givenCommand:  "echo secret",
+			expectedToken: "secret",
+		},
+		"Failure": {
+			givenCommand:  "echo oops >&2; exit 1",
+			expectedError: "token command failed: exit status 1: oops",
+		},
+		"EmptyOutput": {
+			givenCommand:  "true",
+			expectedError: "token command returned an empty token",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := FromCommand(context.Background(), tt.givenCommand)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedToken, result)
+		})
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, StoreInKeyring("https://paperless.example/", "", "token"))
+	require.NoError(t, StoreInKeyring("https://paperless.example", "jane", "password"))
+
+	token, err := FromKeyring("https://paperless.example", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	token, err = FromKeyring("https://paperless.example", "jane")
+	assert.NoError(t, err)
+	assert.Equal(t, "password", token)
+
+	_, err = FromKeyring("https://other.example", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLookupKeyring(t *testing.T) {
+	keyring.MockInit()
+	require.NoError(t, StoreInKeyring("https://paperless.example", "", "token"))
+	require.NoError(t, StoreInKeyring("https://paperless.example", "jane", "password"))
+	tests := map[string]struct {
+		givenURL         string
+		givenUsername    string
+		expectedSecret   string
+		expectedAPIToken bool
+		expectedErr      error
+	}{
+		"TokenWithoutUsername": {
+			givenURL:       "https://paperless.example",
+			expectedSecret: "token", expectedAPIToken: true,
+		},
+		"PasswordOfUser": {
+			givenURL: "https://paperless.example", givenUsername: "jane",
+			expectedSecret: "password",
+		},
+		"TokenIfNoPasswordOfUser": {
+			givenURL: "https://paperless.example", givenUsername: "john",
+			expectedSecret: "token", expectedAPIToken: true,
+		},
+		"NotFound": {
+			givenURL: "https://other.example", givenUsername: "jane",
+			expectedErr: ErrNotFound,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			secret, apiToken, err := LookupKeyring(tt.givenURL, tt.givenUsername)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSecret, secret)
+			assert.Equal(t, tt.expectedAPIToken, apiToken)
+		})
+	}
+}
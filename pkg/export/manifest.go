@@ -0,0 +1,154 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the manifest format written by this package.
+// It is increased whenever the format changes incompatibly.
+const Version = 1
+
+// Format is the file format of a manifest.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Formats contains all supported formats.
+var Formats = []Format{FormatJSON, FormatYAML}
+
+// FileName returns the name of the manifest file in the given format.
+func (f Format) FileName() string {
+	return "export." + string(f)
+}
+
+// ParseFormat returns the format by its name.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q, must be one of %v", name, Formats)
+}
+
+// Manifest is a self-describing export of all documents and their related entities.
+// Together with the downloaded files it contains everything that is needed to rebuild a library.
+type Manifest struct {
+	// Version of the manifest format.
+	Version int `json:"version"`
+	// ExportedAt is the time the manifest was written.
+	ExportedAt time.Time `json:"exported_at"`
+	// Server is the URL of the Paperless instance the documents were exported from.
+	Server string `json:"server,omitempty"`
+
+	Tags           []paperless.Tag           `json:"tags"`
+	Correspondents []paperless.Correspondent `json:"correspondents"`
+	DocumentTypes  []paperless.DocumentType  `json:"document_types"`
+	StoragePaths   []paperless.StoragePath   `json:"storage_paths"`
+	CustomFields   []paperless.CustomField   `json:"custom_fields"`
+	Documents      []Document                `json:"documents"`
+}
+
+// Document is an exported document.
+// Related entities are referenced by their ID in the manifest.
+type Document struct {
+	paperless.Document
+	// Files are the slash-separated paths of the downloaded files, relative to the manifest.
+	Files []string `json:"files,omitempty"`
+}
+
+// Write serializes the manifest in the given format.
+// YAML uses the same keys as JSON.
+func Write(w io.Writer, m *Manifest, format Format) error {
+	m.Version = Version
+	raw, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot serialize manifest: %w", err)
+	}
+	if format == FormatYAML {
+		var generic any
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return fmt.Errorf("cannot serialize manifest: %w", err)
+		}
+		if raw, err = yaml.Marshal(generic); err != nil {
+			return fmt.Errorf("cannot serialize manifest: %w", err)
+		}
+	}
+	_, err = w.Write(raw)
+	return err
+}
+
+// Read parses the manifest in the given format.
+// It returns an error if the manifest was written by a newer, incompatible version.
+func Read(r io.Reader, format Format) (*Manifest, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest: %w", err)
+	}
+	if format == FormatYAML {
+		var generic any
+		if err := yaml.Unmarshal(raw, &generic); err != nil {
+			return nil, fmt.Errorf("cannot parse manifest: %w", err)
+		}
+		if raw, err = json.Marshal(generic); err != nil {
+			return nil, fmt.Errorf("cannot parse manifest: %w", err)
+		}
+	}
+	m := &Manifest{}
+	if err := json.Unmarshal(raw, m); err != nil {
+		return nil, fmt.Errorf("cannot parse manifest: %w", err)
+	}
+	if m.Version < 1 || m.Version > Version {
+		return nil, fmt.Errorf("unsupported manifest version %d, must be between 1 and %d", m.Version, Version)
+	}
+	return m, nil
+}
+
+// WriteFile writes the manifest into the given directory.
+// The file is replaced atomically, so that a failed export doesn't corrupt an existing one.
+func WriteFile(dir string, m *Manifest, format Format) (string, error) {
+	target := filepath.Join(dir, format.FileName())
+	tmpFile, err := os.CreateTemp(dir, ".export-")
+	if err != nil {
+		return "", fmt.Errorf("cannot write manifest: %w", err)
+	}
+	defer os.Remove(tmpFile.Name()) // cleanup if not renamed
+	if err := Write(tmpFile, m, format); err != nil {
+		_ = tmpFile.Close()
+		return "", err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return "", fmt.Errorf("cannot write manifest: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), target); err != nil {
+		return "", fmt.Errorf("cannot write manifest: %w", err)
+	}
+	return target, nil
+}
+
+// ReadFile reads the manifest from the given directory in any of the supported formats.
+func ReadFile(dir string) (*Manifest, error) {
+	for _, format := range Formats {
+		f, err := os.Open(filepath.Join(dir, format.FileName()))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot open manifest: %w", err)
+		}
+		defer f.Close()
+		return Read(f, format)
+	}
+	return nil, fmt.Errorf("no manifest found in %q, expected one of %q or %q", dir, FormatJSON.FileName(), FormatYAML.FileName())
+}
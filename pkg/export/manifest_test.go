@@ -0,0 +1,73 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRead(t *testing.T) {
+	asn := 42
+	given := &Manifest{
+		ExportedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Server:       "https://paperless.example",
+		Tags:         []paperless.Tag{{ID: 1, Name: "Invoice", Matching: paperless.Matching{Match: "bill", MatchingAlgorithm: paperless.MatchAny}}},
+		CustomFields: []paperless.CustomField{{ID: 2, Name: "Amount", DataType: "monetary"}},
+		Documents: []Document{{
+			Document: paperless.Document{
+				ID: 3, Title: "Bill", Tags: []int{1}, ArchiveSerialNumber: &asn,
+				CustomFields: []paperless.CustomFieldInstance{{Field: 2, Value: "EUR12.50"}},
+				Notes:        []paperless.Note{{ID: 4, Note: "paid"}},
+			},
+			Files: []string{"originals/bill.pdf"},
+		}},
+	}
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			require.NoError(t, Write(buf, given, format))
+			result, err := Read(buf, format)
+			require.NoError(t, err)
+			assert.Equal(t, given, result)
+			assert.Equal(t, Version, result.Version)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := map[string]struct {
+		givenContent  string
+		givenFormat   Format
+		expectedError string
+	}{
+		"MissingVersion": {
+			givenContent:  `{"documents": []}`,
+			givenFormat:   FormatJSON,
+			expectedError: "unsupported manifest version 0, must be between 1 and 1",
+		},
+		"NewerVersion": {
+			givenContent:  "version: 99\n",
+			givenFormat:   FormatYAML,
+			expectedError: "unsupported manifest version 99, must be between 1 and 1",
+		},
+		"Valid": {
+			givenContent: "version: 1\ndocuments:\n  - id: 1\n    title: Bill\n",
+			givenFormat:  FormatYAML,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.givenContent), tt.givenFormat)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
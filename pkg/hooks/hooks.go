@@ -0,0 +1,163 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is the outcome of uploading a file.
+type Event string
+
+const (
+	// EventSuccess is fired if the file has been uploaded, or consumed if waiting for the task.
+	EventSuccess Event = "success"
+	// EventFailure is fired if the file could not be uploaded or consumed.
+	EventFailure Event = "failure"
+	// EventDuplicate is fired if Paperless refused to consume the file as it already exists.
+	// It can only be detected if waiting for the task.
+	EventDuplicate Event = "duplicate"
+)
+
+// Events contains all supported events.
+var Events = []Event{EventSuccess, EventFailure, EventDuplicate}
+
+// ParseEvent returns the event with the given name.
+func ParseEvent(name string) (Event, error) {
+	if slices.Contains(Events, Event(name)) {
+		return Event(name), nil
+	}
+	return "", fmt.Errorf("unknown event %q, supported: %v", name, Events)
+}
+
+// Payload is the data of an event.
+type Payload struct {
+	Event Event  `json:"event"`
+	File  string `json:"file"`
+	// TaskID is the ID of the consumption task, empty if the upload failed.
+	TaskID string `json:"task_id,omitempty"`
+	// DocumentID is the ID of the consumed document, 0 if not waiting for the task.
+	DocumentID int       `json:"document_id,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// Hook notifies about events.
+type Hook interface {
+	Fire(ctx context.Context, payload Payload) error
+}
+
+// Hooks fires each hook for the selected events.
+type Hooks struct {
+	hooks  []Hook
+	events []Event
+}
+
+// New returns hooks that are fired for the given events, or for all events if none are given.
+func New(events []Event, hooks ...Hook) *Hooks {
+	if len(events) == 0 {
+		events = Events
+	}
+	return &Hooks{hooks: hooks, events: events}
+}
+
+// Enabled returns true if there's at least one hook.
+func (h *Hooks) Enabled() bool {
+	return h != nil && len(h.hooks) > 0
+}
+
+// Fire fires all hooks if the event of the payload is selected.
+// All hooks are fired even if one fails, the errors are combined.
+func (h *Hooks) Fire(ctx context.Context, payload Payload) error {
+	if !h.Enabled() || !slices.Contains(h.events, payload.Event) {
+		return nil
+	}
+	if payload.Time.IsZero() {
+		payload.Time = time.Now()
+	}
+	errs := make([]error, 0)
+	for _, hook := range h.hooks {
+		if err := hook.Fire(ctx, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Webhook sends the payload as JSON in a POST request.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+// Fire sends the payload to the URL.
+// An error is returned if the response status code isn't in the 2xx range.
+func (w *Webhook) Fire(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("cannot serialize payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot prepare webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook failed: %s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+// ExecHook runs a shell command with the payload in environment variables, e.g. PAPERLESS_EVENT.
+type ExecHook struct {
+	Command string
+}
+
+// Fire runs the command and waits until it has finished.
+func (e *ExecHook) Fire(ctx context.Context, payload Payload) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", e.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", e.Command)
+	}
+	cmd.Env = append(os.Environ(), Environ(payload)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("exec hook failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Environ returns the payload as environment variables in the form "KEY=value".
+func Environ(payload Payload) []string {
+	documentID := ""
+	if payload.DocumentID != 0 {
+		documentID = strconv.Itoa(payload.DocumentID)
+	}
+	return []string{
+		"PAPERLESS_EVENT=" + string(payload.Event),
+		"PAPERLESS_FILE=" + payload.File,
+		"PAPERLESS_TASK_ID=" + payload.TaskID,
+		"PAPERLESS_DOCUMENT_ID=" + documentID,
+		"PAPERLESS_ERROR=" + payload.Error,
+		"PAPERLESS_TIME=" + payload.Time.Format(time.RFC3339),
+	}
+}
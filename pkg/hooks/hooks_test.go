@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingHook struct {
+	fired []Payload
+}
+
+func (r *recordingHook) Fire(_ context.Context, payload Payload) error {
+	r.fired = append(r.fired, payload)
+	return nil
+}
+
+func TestHooks_Fire(t *testing.T) {
+	tests := map[string]struct {
+		givenEvents    []Event
+		givenEvent     Event
+		expectedFiring bool
+	}{
+		"AllEventsByDefault": {
+			givenEvent:     EventDuplicate,
+			expectedFiring: true,
+		},
+		"SelectedEvent": {
+			givenEvents:    []Event{EventFailure, EventDuplicate},
+			givenEvent:     EventFailure,
+			expectedFiring: true,
+		},
+		"OtherEvent": {
+			givenEvents: []Event{EventFailure},
+			givenEvent:  EventSuccess,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			hook := &recordingHook{}
+			err := New(tt.givenEvents, hook).Fire(context.Background(), Payload{Event: tt.givenEvent, File: "invoice.pdf"})
+			require.NoError(t, err)
+			if !tt.expectedFiring {
+				assert.Empty(t, hook.fired)
+				return
+			}
+			require.Len(t, hook.fired, 1)
+			assert.Equal(t, tt.givenEvent, hook.fired[0].Event)
+			assert.False(t, hook.fired[0].Time.IsZero())
+		})
+	}
+}
+
+func TestWebhook_Fire(t *testing.T) {
+	tests := map[string]struct {
+		givenStatus   int
+		expectedError string
+	}{
+		"Success": {
+			givenStatus: http.StatusNoContent,
+		},
+		"ServerError": {
+			givenStatus:   http.StatusInternalServerError,
+			expectedError: "webhook failed: 500 Internal Server Error: broken",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var received Payload
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				if tt.givenStatus != http.StatusNoContent {
+					http.Error(w, "broken", tt.givenStatus)
+					return
+				}
+				w.WriteHeader(tt.givenStatus)
+			}))
+			defer srv.Close()
+
+			hook := &Webhook{URL: srv.URL, Client: srv.Client()}
+			err := hook.Fire(context.Background(), Payload{Event: EventSuccess, File: "invoice.pdf", TaskID: "abc", DocumentID: 12})
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, Payload{Event: EventSuccess, File: "invoice.pdf", TaskID: "abc", DocumentID: 12}, received)
+		})
+	}
+}
+
+func TestExecHook_Fire(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	hook := &ExecHook{Command: `echo "$PAPERLESS_EVENT $PAPERLESS_FILE $PAPERLESS_DOCUMENT_ID $PAPERLESS_ERROR" > ` + out}
+
+	err := hook.Fire(context.Background(), Payload{Event: EventDuplicate, File: "invoice.pdf", Error: "duplicate", Time: time.Now()})
+	require.NoError(t, err)
+	b, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "duplicate invoice.pdf  duplicate\n", string(b))
+
+	err = (&ExecHook{Command: "echo oops; exit 3"}).Fire(context.Background(), Payload{Event: EventFailure})
+	assert.EqualError(t, err, "exec hook failed: exit status 3: oops")
+}
@@ -0,0 +1,133 @@
+package layout
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+)
+
+// NameResolver resolves the names of related entities by their ID.
+type NameResolver interface {
+	TagName(id int) string
+	CorrespondentName(id *int) string
+	DocumentTypeName(id *int) string
+}
+
+// Template renders relative file paths for documents, e.g. "{correspondent}/{created_year}/{title}-{id}".
+type Template struct {
+	format       string
+	hasExtension bool
+}
+
+// emptyValue is used for placeholders that have no value, e.g. a document without correspondent.
+const emptyValue = "none"
+
+var placeholderRegex = regexp.MustCompile(`{([a-z_]+)}`)
+
+var placeholders = map[string]func(doc paperless.Document, names NameResolver) string{
+	"id":    func(doc paperless.Document, _ NameResolver) string { return strconv.Itoa(doc.ID) },
+	"title": func(doc paperless.Document, _ NameResolver) string { return doc.Title },
+	"correspondent": func(doc paperless.Document, names NameResolver) string {
+		return names.CorrespondentName(doc.Correspondent)
+	},
+	"document_type": func(doc paperless.Document, names NameResolver) string {
+		return names.DocumentTypeName(doc.DocumentType)
+	},
+	"tag_list": func(doc paperless.Document, names NameResolver) string {
+		tags := make([]string, 0, len(doc.Tags))
+		for _, id := range doc.Tags {
+			if name := names.TagName(id); name != "" {
+				tags = append(tags, name)
+			}
+		}
+		return strings.Join(tags, ",")
+	},
+	"created":       func(doc paperless.Document, _ NameResolver) string { return formatCreated(doc, "2006-01-02") },
+	"created_year":  func(doc paperless.Document, _ NameResolver) string { return formatCreated(doc, "2006") },
+	"created_month": func(doc paperless.Document, _ NameResolver) string { return formatCreated(doc, "01") },
+	"created_day":   func(doc paperless.Document, _ NameResolver) string { return formatCreated(doc, "02") },
+	"original_name": func(doc paperless.Document, _ NameResolver) string {
+		return strings.TrimSuffix(doc.OriginalFileName, path.Ext(doc.OriginalFileName))
+	},
+}
+
+// Parse validates the given format and returns a new Template.
+func Parse(format string) (*Template, error) {
+	if strings.TrimSpace(format) == "" {
+		return nil, fmt.Errorf("format cannot be empty")
+	}
+	if strings.HasPrefix(format, "/") {
+		return nil, fmt.Errorf("format must be a relative path: %s", format)
+	}
+	for _, match := range placeholderRegex.FindAllStringSubmatch(format, -1) {
+		if _, exists := placeholders[match[1]]; !exists {
+			return nil, fmt.Errorf("unknown placeholder in format: %s", match[0])
+		}
+	}
+	// placeholder values may contain dots, so only literal text counts as file extension
+	literal := placeholderRegex.ReplaceAllString(path.Base(format), "x")
+	return &Template{format: format, hasExtension: path.Ext(literal) != ""}, nil
+}
+
+// Render returns the slash-separated relative path for the given document.
+// Values of placeholders are sanitized so that they can't introduce additional directories.
+// If the format has no literal file extension, the given extension is appended.
+func (t *Template) Render(doc paperless.Document, names NameResolver, extension string) string {
+	rendered := placeholderRegex.ReplaceAllStringFunc(t.format, func(placeholder string) string {
+		key := placeholder[1 : len(placeholder)-1]
+		return sanitize(placeholders[key](doc, names))
+	})
+	segments := strings.Split(rendered, "/")
+	cleaned := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" || segment == "." || segment == ".." {
+			continue
+		}
+		cleaned = append(cleaned, segment)
+	}
+	result := strings.Join(cleaned, "/")
+	if !t.hasExtension {
+		result += extension
+	}
+	return result
+}
+
+// Unique returns the given path, or if it's already taken, the path with a numbered suffix before the extension.
+func Unique(filePath string, isTaken func(string) bool) string {
+	if !isTaken(filePath) {
+		return filePath
+	}
+	ext := path.Ext(filePath)
+	base := strings.TrimSuffix(filePath, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s_%02d%s", base, i, ext)
+		if !isTaken(candidate) {
+			return candidate
+		}
+	}
+}
+
+func formatCreated(doc paperless.Document, layout string) string {
+	created := doc.CreatedDate()
+	if created.IsZero() {
+		return ""
+	}
+	return created.Format(layout)
+}
+
+var invalidCharsReplacer = strings.NewReplacer(
+	"/", "-", "\\", "-", ":", "-", "*", "", "?", "", "\"", "", "<", "", ">", "", "|", "", "\x00", "",
+)
+
+func sanitize(value string) string {
+	value = strings.TrimSpace(invalidCharsReplacer.Replace(value))
+	if value == "" || value == "." || value == ".." {
+		return emptyValue
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/stretchr/testify/assert"
+)
+
+type names struct{}
+
+func (n names) TagName(id int) string {
+	return map[int]string{1: "Invoice", 2: "Home"}[id]
+}
+
+func (n names) CorrespondentName(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return "Power Company"
+}
+
+func (n names) DocumentTypeName(_ *int) string {
+	return ""
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		givenFormat   string
+		expectedError string
+	}{
+		"ValidFormat": {
+			givenFormat: "{correspondent}/{created_year}/{title}-{id}.pdf",
+		},
+		"EmptyFormat": {
+			givenFormat:   " ",
+			expectedError: "format cannot be empty",
+		},
+		"AbsolutePath": {
+			givenFormat:   "/{title}",
+			expectedError: "format must be a relative path: /{title}",
+		},
+		"UnknownPlaceholder": {
+			givenFormat:   "{title}/{unknown}",
+			expectedError: "unknown placeholder in format: {unknown}",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tt.givenFormat)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	correspondent := 1
+	tests := map[string]struct {
+		givenFormat   string
+		givenDocument paperless.Document
+		expectedPath  string
+	}{
+		"AllPlaceholders": {
+			givenFormat: "{correspondent}/{created_year}/{created}-{title}-{id}",
+			givenDocument: paperless.Document{
+				ID: 5, Title: "Bill", Created: "2023-02-01T00:00:00+01:00", Correspondent: &correspondent,
+			},
+			expectedPath: "Power Company/2023/2023-02-01-Bill-5.pdf",
+		},
+		"ExplicitExtension": {
+			givenFormat:   "{title}.txt",
+			givenDocument: paperless.Document{Title: "Bill"},
+			expectedPath:  "Bill.txt",
+		},
+		"EmptyValues": {
+			givenFormat:   "{correspondent}/{document_type}/{title}",
+			givenDocument: paperless.Document{Title: "Bill"},
+			expectedPath:  "none/none/Bill.pdf",
+		},
+		"TitleWithSlashes": {
+			givenFormat:   "{title}",
+			givenDocument: paperless.Document{Title: "../2023/01 Bill"},
+			expectedPath:  "..-2023-01 Bill.pdf",
+		},
+		"Tags": {
+			givenFormat:   "{tag_list}/{original_name}",
+			givenDocument: paperless.Document{Tags: []int{1, 2}, OriginalFileName: "scan.jpg"},
+			expectedPath:  "Invoice,Home/scan.pdf",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpl, err := Parse(tt.givenFormat)
+			assert.NoError(t, err)
+			result := tmpl.Render(tt.givenDocument, names{}, ".pdf")
+			assert.Equal(t, tt.expectedPath, result)
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	taken := map[string]bool{"dir/file.pdf": true, "dir/file_01.pdf": true}
+	tests := map[string]struct {
+		givenPath    string
+		expectedPath string
+	}{
+		"NotTaken": {
+			givenPath:    "dir/other.pdf",
+			expectedPath: "dir/other.pdf",
+		},
+		"Taken": {
+			givenPath:    "dir/file.pdf",
+			expectedPath: "dir/file_02.pdf",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Unique(tt.givenPath, func(p string) bool { return taken[p] })
+			assert.Equal(t, tt.expectedPath, result)
+		})
+	}
+}
@@ -5,22 +5,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 
 	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/layout"
 	"github.com/ccremer/paperless-cli/pkg/paperless"
 )
 
 type metadataContainer struct {
-	Documents []paperless.Document `json:"documents,omitempty"`
+	Documents      []paperless.Document      `json:"documents,omitempty"`
+	Tags           []paperless.Tag           `json:"tags,omitempty"`
+	Correspondents []paperless.Correspondent `json:"correspondents,omitempty"`
+	DocumentTypes  []paperless.DocumentType  `json:"document_types,omitempty"`
+	Files          map[int][]string          `json:"files,omitempty"`
 }
 
 var fileName = ".metadata.json"
 
 // Database is a simple wrapper around a JSON-based file.
 type Database struct {
-	documents map[int]paperless.Document
-	filePath  string
+	documents      map[int]paperless.Document
+	tags           []paperless.Tag
+	correspondents []paperless.Correspondent
+	documentTypes  []paperless.DocumentType
+	files          map[int][]string
+	filePath       string
 }
 
 // Open reads the database file from the given directory.
@@ -43,9 +53,16 @@ func Open(documentDir string) (*Database, error) {
 		return nil, fmt.Errorf("cannot parse metadata file %s: %w", filePath, err)
 	}
 	docs := paperless.MapToDocumentMap(container.Documents)
+	if container.Files == nil {
+		container.Files = map[int][]string{}
+	}
 	return &Database{
-		filePath:  filePath,
-		documents: docs,
+		filePath:       filePath,
+		documents:      docs,
+		tags:           container.Tags,
+		correspondents: container.Correspondents,
+		documentTypes:  container.DocumentTypes,
+		files:          container.Files,
 	}, nil
 }
 
@@ -76,14 +93,98 @@ func (d *Database) Put(doc paperless.Document) {
 	d.documents[doc.ID] = doc
 }
 
-// Remove deletes the given document
+// Remove deletes the given document and its file paths.
 func (d *Database) Remove(doc paperless.Document) {
 	delete(d.documents, doc.ID)
+	delete(d.files, doc.ID)
+}
+
+// GetFiles returns the local file paths of the given document ID, relative to the document dir.
+func (d *Database) GetFiles(id int) []string {
+	return d.files[id]
+}
+
+// SetFiles replaces the local file paths of the given document ID, relative to the document dir.
+func (d *Database) SetFiles(id int, paths []string) {
+	if len(paths) == 0 {
+		delete(d.files, id)
+		return
+	}
+	d.files[id] = paths
+}
+
+// FindByFile returns the ID of the document that owns the given local file path.
+// It returns false if the path doesn't belong to any document.
+func (d *Database) FindByFile(path string) (int, bool) {
+	for id, paths := range d.files {
+		for _, p := range paths {
+			if p == path {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// ReplaceFiles replaces the local file paths of the given document ID like SetFiles.
+// It returns the previous paths that aren't used by the document anymore and can be removed.
+// Paths that are owned by another document in the meantime, e.g. if documents swapped their names, are never returned.
+func (d *Database) ReplaceFiles(id int, paths []string) []string {
+	unused := make([]string, 0)
+	for _, oldPath := range d.files[id] {
+		if !slices.Contains(paths, oldPath) && !d.isOwnedByOther(id, oldPath) {
+			unused = append(unused, oldPath)
+		}
+	}
+	d.SetFiles(id, paths)
+	return unused
+}
+
+// UniquePath returns the given path relative to the document dir, or a numbered variant of it,
+// if it's owned by another document or if it's an unknown file in the document dir.
+func (d *Database) UniquePath(id int, path string) string {
+	return layout.Unique(path, func(p string) bool {
+		if owner, found := d.FindByFile(p); found {
+			return owner != id
+		}
+		_, err := os.Stat(filepath.Join(filepath.Dir(d.filePath), filepath.FromSlash(p)))
+		return err == nil
+	})
+}
+
+func (d *Database) isOwnedByOther(id int, path string) bool {
+	for otherID, paths := range d.files {
+		if otherID != id && slices.Contains(paths, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces all known tags.
+func (d *Database) SetTags(tags []paperless.Tag) {
+	d.tags = tags
+}
+
+// SetCorrespondents replaces all known correspondents.
+func (d *Database) SetCorrespondents(correspondents []paperless.Correspondent) {
+	d.correspondents = correspondents
+}
+
+// SetDocumentTypes replaces all known document types.
+func (d *Database) SetDocumentTypes(documentTypes []paperless.DocumentType) {
+	d.documentTypes = documentTypes
 }
 
 // Close saves the database.
 func (d *Database) Close() error {
-	container := metadataContainer{Documents: d.GetAll()}
+	container := metadataContainer{
+		Documents:      d.GetAll(),
+		Tags:           d.tags,
+		Correspondents: d.correspondents,
+		DocumentTypes:  d.documentTypes,
+		Files:          d.files,
+	}
 	b, err := json.Marshal(container)
 	if err != nil {
 		return fmt.Errorf("cannot save database: %w", err)
@@ -1,10 +1,14 @@
 package localdb
 
 import (
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/ccremer/paperless-cli/pkg/paperless"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOpen(t *testing.T) {
@@ -68,3 +72,133 @@ func TestDatabase_GetAll(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabase_FindByFile(t *testing.T) {
+	db := &Database{files: map[int][]string{
+		1: {"archive/2023/bill.pdf", "originals/2023/bill.jpg"},
+		2: {"archive/2023/contract.pdf"},
+	}}
+	tests := map[string]struct {
+		givenPath     string
+		expectedID    int
+		expectedFound bool
+	}{
+		"KnownFile": {
+			givenPath:     "originals/2023/bill.jpg",
+			expectedID:    1,
+			expectedFound: true,
+		},
+		"UnknownFile": {
+			givenPath:     "archive/2023/bill.jpg",
+			expectedFound: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, found := db.FindByFile(tt.givenPath)
+			assert.Equal(t, tt.expectedID, id)
+			assert.Equal(t, tt.expectedFound, found)
+		})
+	}
+}
+
+func TestDatabase_UniquePath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unknown.pdf"), []byte{}, 0644))
+	db := &Database{filePath: filepath.Join(dir, fileName), files: map[int][]string{
+		1: {"bill.pdf"},
+		2: {"contract.pdf"},
+	}}
+	tests := map[string]struct {
+		givenID      int
+		givenPath    string
+		expectedPath string
+	}{
+		"FreePath": {
+			givenID: 1, givenPath: "new.pdf",
+			expectedPath: "new.pdf",
+		},
+		"OwnPath": {
+			givenID: 1, givenPath: "bill.pdf",
+			expectedPath: "bill.pdf",
+		},
+		"PathOfOtherDocument": {
+			givenID: 1, givenPath: "contract.pdf",
+			expectedPath: "contract_01.pdf",
+		},
+		"UnknownFile": {
+			givenID: 1, givenPath: "unknown.pdf",
+			expectedPath: "unknown_01.pdf",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedPath, db.UniquePath(tt.givenID, tt.givenPath))
+		})
+	}
+}
+
+func TestDatabase_ReplaceFiles(t *testing.T) {
+	tests := map[string]struct {
+		givenFiles     map[int][]string
+		givenID        int
+		givenPaths     []string
+		expectedUnused []string
+		expectedFiles  map[int][]string
+	}{
+		"Renamed": {
+			givenFiles: map[int][]string{1: {"bill.pdf"}},
+			givenID:    1, givenPaths: []string{"invoice.pdf"},
+			expectedUnused: []string{"bill.pdf"},
+			expectedFiles:  map[int][]string{1: {"invoice.pdf"}},
+		},
+		"Unchanged": {
+			givenFiles: map[int][]string{1: {"bill.pdf"}},
+			givenID:    1, givenPaths: []string{"bill.pdf"},
+			expectedUnused: []string{},
+			expectedFiles:  map[int][]string{1: {"bill.pdf"}},
+		},
+		"PathOwnedByOtherDocument": {
+			// the other document has taken over the path already
+			givenFiles: map[int][]string{1: {"x.pdf"}, 2: {"x.pdf"}},
+			givenID:    1, givenPaths: []string{"y.pdf"},
+			expectedUnused: []string{},
+			expectedFiles:  map[int][]string{1: {"y.pdf"}, 2: {"x.pdf"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			db := &Database{files: tt.givenFiles}
+			assert.Equal(t, tt.expectedUnused, db.ReplaceFiles(tt.givenID, tt.givenPaths))
+			assert.Equal(t, tt.expectedFiles, db.files)
+		})
+	}
+}
+
+func TestDatabase_SwappedNames(t *testing.T) {
+	dir := t.TempDir()
+	db := &Database{filePath: filepath.Join(dir, fileName), files: map[int][]string{
+		1: {"x.pdf"},
+		2: {"y.pdf"},
+	}}
+	for _, p := range []string{"x.pdf", "y.pdf"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, p), []byte(p), 0644))
+	}
+	// the documents swapped their names on the server and are downloaded one after the other
+	download := func(id int, name string) {
+		p := db.UniquePath(id, name)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, p), []byte(strconv.Itoa(id)), 0644))
+		for _, unused := range db.ReplaceFiles(id, []string{p}) {
+			require.NoError(t, os.Remove(filepath.Join(dir, unused)))
+		}
+	}
+	download(1, "y.pdf")
+	download(2, "x.pdf")
+
+	assert.Equal(t, map[int][]string{1: {"y_01.pdf"}, 2: {"x.pdf"}}, db.files)
+	for id, paths := range db.files {
+		content, err := os.ReadFile(filepath.Join(dir, paths[0]))
+		require.NoError(t, err, "file of document %d must exist", id)
+		assert.Equal(t, strconv.Itoa(id), string(content))
+	}
+}
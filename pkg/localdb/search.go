@@ -0,0 +1,121 @@
+package localdb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+)
+
+// SearchQuery contains the criteria to find documents in the database.
+// Empty criteria are ignored, all others have to match.
+// Text comparisons are case-insensitive.
+type SearchQuery struct {
+	// Text has to be contained in either the title or the content.
+	Text string
+	// Title has to be contained in the title.
+	Title string
+	// Content has to be contained in the content.
+	Content string
+	// Tags are names of tags that all have to be assigned.
+	Tags []string
+	// Correspondent is the name of the assigned correspondent.
+	Correspondent string
+	// DocumentType is the name of the assigned document type.
+	DocumentType string
+	// CreatedAfter matches documents created on or after the given date.
+	CreatedAfter time.Time
+	// CreatedBefore matches documents created on or before the given date.
+	CreatedBefore time.Time
+}
+
+// Search returns all documents matching the given query, sorted by ID.
+func (d *Database) Search(q SearchQuery) []paperless.Document {
+	result := make([]paperless.Document, 0)
+	for _, doc := range d.GetAll() {
+		if d.matches(doc, q) {
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+func (d *Database) matches(doc paperless.Document, q SearchQuery) bool {
+	if q.Text != "" && !containsFold(doc.Title, q.Text) && !containsFold(doc.Content, q.Text) {
+		return false
+	}
+	if q.Title != "" && !containsFold(doc.Title, q.Title) {
+		return false
+	}
+	if q.Content != "" && !containsFold(doc.Content, q.Content) {
+		return false
+	}
+	if q.Correspondent != "" && !strings.EqualFold(d.CorrespondentName(doc.Correspondent), q.Correspondent) {
+		return false
+	}
+	if q.DocumentType != "" && !strings.EqualFold(d.DocumentTypeName(doc.DocumentType), q.DocumentType) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !d.hasTag(doc, tag) {
+			return false
+		}
+	}
+	created := doc.CreatedDate()
+	if !q.CreatedAfter.IsZero() && (created.IsZero() || created.Before(q.CreatedAfter)) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && (created.IsZero() || created.After(q.CreatedBefore)) {
+		return false
+	}
+	return true
+}
+
+func (d *Database) hasTag(doc paperless.Document, name string) bool {
+	for _, id := range doc.Tags {
+		if strings.EqualFold(d.TagName(id), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// TagName returns the name of the tag with the given ID, or an empty string if unknown.
+func (d *Database) TagName(id int) string {
+	for _, tag := range d.tags {
+		if tag.ID == id {
+			return tag.Name
+		}
+	}
+	return ""
+}
+
+// CorrespondentName returns the name of the correspondent with the given ID, or an empty string if nil or unknown.
+func (d *Database) CorrespondentName(id *int) string {
+	if id == nil {
+		return ""
+	}
+	for _, correspondent := range d.correspondents {
+		if correspondent.ID == *id {
+			return correspondent.Name
+		}
+	}
+	return ""
+}
+
+// DocumentTypeName returns the name of the document type with the given ID, or an empty string if nil or unknown.
+func (d *Database) DocumentTypeName(id *int) string {
+	if id == nil {
+		return ""
+	}
+	for _, documentType := range d.documentTypes {
+		if documentType.ID == *id {
+			return documentType.Name
+		}
+	}
+	return ""
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
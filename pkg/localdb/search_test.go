@@ -0,0 +1,70 @@
+package localdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_Search(t *testing.T) {
+	correspondent := 1
+	documentType := 2
+	db := &Database{
+		documents: map[int]paperless.Document{
+			1: {ID: 1, Title: "Electricity Bill", Content: "amount due", Created: "2023-02-01", Tags: []int{10, 11}, Correspondent: &correspondent},
+			2: {ID: 2, Title: "Rental Contract", Content: "the tenant agrees", Created: "2021-06-15T00:00:00+02:00", Tags: []int{11}, DocumentType: &documentType},
+			3: {ID: 3, Title: "Untitled"},
+		},
+		tags:           []paperless.Tag{{ID: 10, Name: "Invoice"}, {ID: 11, Name: "Home"}},
+		correspondents: []paperless.Correspondent{{ID: 1, Name: "Power Company"}},
+		documentTypes:  []paperless.DocumentType{{ID: 2, Name: "Contract"}},
+	}
+	tests := map[string]struct {
+		givenQuery  SearchQuery
+		expectedIDs []int
+	}{
+		"EmptyQuery_MatchAll": {
+			givenQuery:  SearchQuery{},
+			expectedIDs: []int{1, 2, 3},
+		},
+		"Text_MatchesTitleOrContent": {
+			givenQuery:  SearchQuery{Text: "TENANT"},
+			expectedIDs: []int{2},
+		},
+		"Title": {
+			givenQuery:  SearchQuery{Title: "bill"},
+			expectedIDs: []int{1},
+		},
+		"Tags_AllRequired": {
+			givenQuery:  SearchQuery{Tags: []string{"home", "invoice"}},
+			expectedIDs: []int{1},
+		},
+		"Correspondent": {
+			givenQuery:  SearchQuery{Correspondent: "power company"},
+			expectedIDs: []int{1},
+		},
+		"DocumentType": {
+			givenQuery:  SearchQuery{DocumentType: "Contract"},
+			expectedIDs: []int{2},
+		},
+		"CreatedRange": {
+			givenQuery: SearchQuery{
+				CreatedAfter:  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedBefore: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC),
+			},
+			expectedIDs: []int{2},
+		},
+		"NoMatch": {
+			givenQuery:  SearchQuery{Text: "nonexisting"},
+			expectedIDs: []int{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := db.Search(tt.givenQuery)
+			assert.Equal(t, tt.expectedIDs, paperless.MapToDocumentIDs(result))
+		})
+	}
+}
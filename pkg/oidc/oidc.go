@@ -0,0 +1,140 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Flow is the OAuth2 grant used to obtain tokens.
+type Flow string
+
+const (
+	// FlowClientCredentials obtains tokens non-interactively with the client ID and secret, e.g. for service accounts.
+	FlowClientCredentials Flow = "client-credentials"
+	// FlowDeviceCode obtains tokens by letting the user log in on another device with a browser.
+	FlowDeviceCode Flow = "device-code"
+)
+
+// Flows contains all supported flows.
+var Flows = []Flow{FlowClientCredentials, FlowDeviceCode}
+
+// ParseFlow returns the flow with the given name.
+func ParseFlow(name string) (Flow, error) {
+	for _, flow := range Flows {
+		if string(flow) == name {
+			return flow, nil
+		}
+	}
+	return "", fmt.Errorf("unknown OIDC flow %q, supported: [%s, %s]", name, FlowClientCredentials, FlowDeviceCode)
+}
+
+// Config configures how tokens are obtained from the OIDC issuer.
+type Config struct {
+	// Issuer is the URL of the OIDC provider, used for the discovery of the endpoints.
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Flow         Flow
+}
+
+// Endpoints are the endpoints of an OIDC provider.
+type Endpoints struct {
+	TokenURL      string `json:"token_endpoint"`
+	DeviceAuthURL string `json:"device_authorization_endpoint"`
+}
+
+// DeviceCodePrompt is called during the device code flow to show the user where to log in.
+type DeviceCodePrompt func(verificationURI, userCode string)
+
+// Discover fetches the endpoints of the OIDC provider from its discovery document.
+func Discover(ctx context.Context, httpClient *http.Client, issuer string) (*Endpoints, error) {
+	discoveryURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, "GET", discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prepare request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot discover OIDC endpoints: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot discover OIDC endpoints: %s: %s", resp.Status, string(b))
+	}
+	endpoints := &Endpoints{}
+	if err := json.Unmarshal(b, endpoints); err != nil {
+		return nil, fmt.Errorf("cannot parse OIDC discovery document: %w", err)
+	}
+	if endpoints.TokenURL == "" {
+		return nil, fmt.Errorf("OIDC discovery document of %s has no token endpoint", issuer)
+	}
+	return endpoints, nil
+}
+
+// NewTokenSource obtains a token from the issuer and returns a source that refreshes it before it expires.
+// The given context and HTTP client are used for all requests to the issuer, also for later refreshes.
+// For FlowDeviceCode, prompt is called with the URL where the user has to log in, and it blocks until the user has logged in.
+func NewTokenSource(ctx context.Context, httpClient *http.Client, cfg Config, prompt DeviceCodePrompt) (oauth2.TokenSource, error) {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	endpoints, err := Discover(ctx, httpClient, cfg.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	var source oauth2.TokenSource
+	switch cfg.Flow {
+	case FlowClientCredentials:
+		ccConfig := &clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     endpoints.TokenURL,
+			Scopes:       cfg.Scopes,
+		}
+		source = ccConfig.TokenSource(ctx)
+	case FlowDeviceCode:
+		if endpoints.DeviceAuthURL == "" {
+			return nil, fmt.Errorf("OIDC issuer %s doesn't support the device code flow", cfg.Issuer)
+		}
+		oauthConfig := &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     oauth2.Endpoint{TokenURL: endpoints.TokenURL, DeviceAuthURL: endpoints.DeviceAuthURL},
+			Scopes:       cfg.Scopes,
+		}
+		deviceAuth, err := oauthConfig.DeviceAuth(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cannot start device code flow: %w", err)
+		}
+		verificationURI := deviceAuth.VerificationURIComplete
+		if verificationURI == "" {
+			verificationURI = deviceAuth.VerificationURI
+		}
+		prompt(verificationURI, deviceAuth.UserCode)
+		token, err := oauthConfig.DeviceAccessToken(ctx, deviceAuth)
+		if err != nil {
+			return nil, fmt.Errorf("device code flow failed: %w", err)
+		}
+		// the token is refreshed with its refresh token
+		source = oauthConfig.TokenSource(ctx, token)
+	default:
+		return nil, fmt.Errorf("unknown OIDC flow %q", cfg.Flow)
+	}
+
+	// fail early if the credentials are wrong
+	if _, err := source.Token(); err != nil {
+		return nil, fmt.Errorf("cannot obtain OIDC token: %w", err)
+	}
+	return source, nil
+}
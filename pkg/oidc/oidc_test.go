@@ -0,0 +1,83 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlow(t *testing.T) {
+	tests := map[string]struct {
+		givenName     string
+		expectedFlow  Flow
+		expectedError string
+	}{
+		"ClientCredentials": {
+			givenName:    "client-credentials",
+			expectedFlow: FlowClientCredentials,
+		},
+		"DeviceCode": {
+			givenName:    "device-code",
+			expectedFlow: FlowDeviceCode,
+		},
+		"Unknown": {
+			givenName:     "password",
+			expectedError: `unknown OIDC flow "password", supported: [client-credentials, device-code]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseFlow(tt.givenName)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFlow, result)
+		})
+	}
+}
+
+func TestNewTokenSource_ClientCredentials(t *testing.T) {
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/realms/test/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token_endpoint": "` + server.URL + `/realms/test/token"}`))
+	})
+	mux.HandleFunc("/realms/test/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		// expires immediately, so that each use needs a new token
+		_, _ = w.Write([]byte(`{"access_token": "access", "token_type": "Bearer", "expires_in": 1}`))
+	})
+
+	source, err := NewTokenSource(context.Background(), server.Client(), Config{
+		Issuer:       server.URL + "/realms/test/",
+		ClientID:     "paperless-cli",
+		ClientSecret: "secret",
+		Flow:         FlowClientCredentials,
+	}, nil)
+	require.NoError(t, err)
+	token, err := source.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, 2, tokenRequests, "token not refreshed")
+}
+
+func TestDiscover_MissingTokenEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"issuer": "test"}`))
+	}))
+	defer server.Close()
+
+	_, err := Discover(context.Background(), server.Client(), server.URL)
+	assert.EqualError(t, err, "OIDC discovery document of "+server.URL+" has no token endpoint")
+}
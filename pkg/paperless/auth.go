@@ -0,0 +1,121 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// User is the user that is authenticated by the credentials of the Client.
+type User struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	IsSuperuser bool   `json:"is_superuser"`
+	// Permissions are the codenames of the global permissions of the user, e.g. "view_document".
+	Permissions []string `json:"-"`
+}
+
+// HasPermission returns true if the user has the given permission, e.g. "view_document".
+func (u User) HasPermission(codename string) bool {
+	if u.IsSuperuser {
+		return true
+	}
+	for _, permission := range u.Permissions {
+		if permission == codename {
+			return true
+		}
+	}
+	return false
+}
+
+// ObtainToken exchanges the given username and password for the API token of the user.
+// The credentials of the Client are not used.
+func (clt *Client) ObtainToken(ctx context.Context, username, password string) (string, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	body, err := json.Marshal(map[string]string{"username": username, "password": password})
+	if err != nil {
+		return "", fmt.Errorf("cannot serialize credentials: %w", err)
+	}
+	log.V(1).Info("Obtaining token", "username", username)
+	req, err := http.NewRequestWithContext(ctx, "POST", clt.URL+"/api/token/", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("cannot prepare request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := clt.do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("cannot read body: %w", err)
+	}
+	if resp.StatusCode == http.StatusBadRequest {
+		return "", fmt.Errorf("login failed: %s", parseTokenError(b))
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("request failed: %s: %s", resp.Status, string(b))
+	}
+	result := struct {
+		Token string `json:"token"`
+	}{}
+	if parseErr := json.Unmarshal(b, &result); parseErr != nil {
+		return "", fmt.Errorf("cannot parse JSON: %w", parseErr)
+	}
+	if result.Token == "" {
+		return "", fmt.Errorf("server returned an empty token")
+	}
+	return result.Token, nil
+}
+
+// GetCurrentUser returns the user that is authenticated by the credentials of the Client.
+// It's useful to verify the credentials.
+func (clt *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	req, err := clt.newRequest(ctx, "GET", "/api/ui_settings/", nil)
+	if err != nil {
+		return nil, err
+	}
+	result := struct {
+		User        User     `json:"user"`
+		Permissions []string `json:"permissions"`
+	}{}
+	if err := clt.doJSON(req, &result); err != nil {
+		return nil, err
+	}
+	result.User.Permissions = result.Permissions
+	return &result.User, nil
+}
+
+// parseTokenError returns the validation messages of a failed token request,
+// e.g. {"non_field_errors": ["Unable to log in with provided credentials."]}.
+// It returns the raw body if it can't be parsed.
+func parseTokenError(body []byte) string {
+	fields := map[string][]string{}
+	if err := json.Unmarshal(body, &fields); err != nil || len(fields) == 0 {
+		return string(body)
+	}
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	messages := make([]string, 0, len(fields))
+	for _, field := range names {
+		msg := strings.Join(fields[field], " ")
+		if field != "non_field_errors" {
+			msg = field + ": " + msg
+		}
+		messages = append(messages, msg)
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,58 @@
+package paperless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTokenError(t *testing.T) {
+	tests := map[string]struct {
+		givenBody       string
+		expectedMessage string
+	}{
+		"NonFieldErrors": {
+			givenBody:       `{"non_field_errors": ["Unable to log in with provided credentials."]}`,
+			expectedMessage: "Unable to log in with provided credentials.",
+		},
+		"FieldErrors": {
+			givenBody:       `{"username": ["This field may not be blank."], "password": ["This field may not be blank."]}`,
+			expectedMessage: "password: This field may not be blank.; username: This field may not be blank.",
+		},
+		"NoJSON": {
+			givenBody:       `Bad Request`,
+			expectedMessage: "Bad Request",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := parseTokenError([]byte(tt.givenBody))
+			assert.Equal(t, tt.expectedMessage, result)
+		})
+	}
+}
+
+func TestUser_HasPermission(t *testing.T) {
+	tests := map[string]struct {
+		givenUser      User
+		expectedResult bool
+	}{
+		"Superuser": {
+			givenUser:      User{IsSuperuser: true},
+			expectedResult: true,
+		},
+		"Granted": {
+			givenUser:      User{Permissions: []string{"view_tag", "view_document"}},
+			expectedResult: true,
+		},
+		"Missing": {
+			givenUser:      User{Permissions: []string{"view_tag"}},
+			expectedResult: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedResult, tt.givenUser.HasPermission("view_document"))
+		})
+	}
+}
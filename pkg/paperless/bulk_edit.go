@@ -0,0 +1,107 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+type BulkEditMethod string
+
+const (
+	BulkEditSetCorrespondent   BulkEditMethod = "set_correspondent"
+	BulkEditSetDocumentType    BulkEditMethod = "set_document_type"
+	BulkEditSetStoragePath     BulkEditMethod = "set_storage_path"
+	BulkEditModifyTags         BulkEditMethod = "modify_tags"
+	BulkEditModifyCustomFields BulkEditMethod = "modify_custom_fields"
+	BulkEditRotate             BulkEditMethod = "rotate"
+	BulkEditMerge              BulkEditMethod = "merge"
+	BulkEditDelete             BulkEditMethod = "delete"
+	BulkEditReprocess          BulkEditMethod = "reprocess"
+)
+
+// String implements fmt.Stringer.
+func (m BulkEditMethod) String() string {
+	return string(m)
+}
+
+type BulkEditParams struct {
+	DocumentIDs []int
+	Method      BulkEditMethod
+	// Parameters depend on the method, e.g. {"correspondent": 1} for BulkEditSetCorrespondent.
+	Parameters map[string]any
+}
+
+// BulkEdit applies the operation given by BulkEditParams.Method to all documents in BulkEditParams.DocumentIDs.
+func (clt *Client) BulkEdit(ctx context.Context, params BulkEditParams) error {
+	log := logr.FromContextOrDiscard(ctx)
+
+	parameters := params.Parameters
+	if parameters == nil {
+		parameters = map[string]any{}
+	}
+	js := map[string]any{
+		"documents":  params.DocumentIDs,
+		"method":     params.Method,
+		"parameters": parameters,
+	}
+	marshal, err := json.Marshal(js)
+	if err != nil {
+		return fmt.Errorf("cannot serialize to JSON: %w", err)
+	}
+
+	log.V(1).Info("Preparing bulk edit", "method", params.Method, "document_ids", params.DocumentIDs)
+	req, err := clt.newRequest(ctx, "POST", "/api/documents/bulk_edit/", bytes.NewReader(marshal))
+	if err != nil {
+		return err
+	}
+	return clt.doJSON(req, nil)
+}
+
+// SetCorrespondentParams returns the parameters for BulkEditSetCorrespondent.
+// If id is nil, the correspondent is removed.
+func SetCorrespondentParams(id *int) map[string]any {
+	return map[string]any{"correspondent": id}
+}
+
+// SetDocumentTypeParams returns the parameters for BulkEditSetDocumentType.
+// If id is nil, the document type is removed.
+func SetDocumentTypeParams(id *int) map[string]any {
+	return map[string]any{"document_type": id}
+}
+
+// SetStoragePathParams returns the parameters for BulkEditSetStoragePath.
+// If id is nil, the storage path is removed.
+func SetStoragePathParams(id *int) map[string]any {
+	return map[string]any{"storage_path": id}
+}
+
+// ModifyTagsParams returns the parameters for BulkEditModifyTags.
+func ModifyTagsParams(addTagIDs, removeTagIDs []int) map[string]any {
+	return map[string]any{"add_tags": nonNil(addTagIDs), "remove_tags": nonNil(removeTagIDs)}
+}
+
+// ModifyCustomFieldsParams returns the parameters for BulkEditModifyCustomFields.
+// The keys of addFields are the IDs of the custom fields to set to the corresponding value.
+func ModifyCustomFieldsParams(addFields map[int]any, removeFieldIDs []int) map[string]any {
+	add := map[string]any{}
+	for id, value := range addFields {
+		add[fmt.Sprintf("%d", id)] = value
+	}
+	return map[string]any{"add_custom_fields": add, "remove_custom_fields": nonNil(removeFieldIDs)}
+}
+
+// RotateParams returns the parameters for BulkEditRotate.
+func RotateParams(degrees int) map[string]any {
+	return map[string]any{"degrees": degrees}
+}
+
+func nonNil(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
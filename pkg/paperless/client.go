@@ -1,7 +1,14 @@
 package paperless
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
+
+	"github.com/go-logr/logr"
 )
 
 type Client struct {
@@ -10,6 +17,26 @@ type Client struct {
 
 	username string
 	token    string
+
+	versionMutex  sync.Mutex
+	apiVersion    int
+	serverVersion ServerVersion
+}
+
+// StatusError is returned if the server responds with an unexpected HTTP status code.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed: %s: %s", e.Status, e.Body)
+}
+
+// IsUnauthorized returns true if the server rejected the credentials or the user lacks permissions.
+func (e *StatusError) IsUnauthorized() bool {
+	return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
 }
 
 // NewClient creates a new PaperlessClient using the given URL and credentials.
@@ -20,13 +47,62 @@ func NewClient(url, username, passwordOrToken string) *Client {
 		HttpClient: http.DefaultClient,
 		username:   username,
 		token:      passwordOrToken,
+		apiVersion: MaxAPIVersion,
 	}
 }
 
+// setAuth sets the Authorization header of the request.
+// No header is set without credentials, e.g. if the transport authenticates the requests with a bearer token.
 func (clt *Client) setAuth(req *http.Request) {
+	if clt.username == "" && clt.token == "" {
+		return
+	}
 	if clt.username == "" {
 		req.Header.Set("Authorization", "Token "+clt.token)
 	} else {
 		req.SetBasicAuth(clt.username, clt.token)
 	}
 }
+
+// newRequest prepares an authenticated request against the given API path.
+func (clt *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	log.V(1).Info("Preparing request", "method", method, "path", path)
+	req, err := http.NewRequestWithContext(ctx, method, clt.URL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot prepare request: %w", err)
+	}
+	clt.setAuth(req)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// doJSON sends the request and parses the response body into result.
+// The result is ignored if nil.
+// An error is returned if the response status code isn't in the 2xx range.
+func (clt *Client) doJSON(req *http.Request, result any) error {
+	log := logr.FromContextOrDiscard(req.Context())
+	log.V(1).Info("Awaiting response")
+	resp, err := clt.do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read body: %w", err)
+	}
+	log.V(2).Info("Read response", "body", string(b))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Body: string(b)}
+	}
+	if result == nil || len(b) == 0 {
+		return nil
+	}
+	if parseErr := json.Unmarshal(b, result); parseErr != nil {
+		return fmt.Errorf("cannot parse JSON: %w", parseErr)
+	}
+	return nil
+}
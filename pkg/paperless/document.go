@@ -1,13 +1,71 @@
 package paperless
 
+import (
+	"time"
+)
+
 type Document struct {
 	// ID of the document, read-only.
 	ID int `json:"id"`
+	// Title of the document.
+	Title string `json:"title,omitempty"`
+	// Content is the plain text content of the document, as extracted by OCR.
+	// May be empty or truncated depending on the query.
+	Content string `json:"content,omitempty"`
+	// Created is the date the document was created, as reported by the API.
+	Created string `json:"created,omitempty"`
+	// Modified is the timestamp of the last change of the document, read-only.
+	Modified string `json:"modified,omitempty"`
+	// Correspondent is the ID of the assigned correspondent, if any.
+	Correspondent *int `json:"correspondent,omitempty"`
+	// DocumentType is the ID of the assigned document type, if any.
+	DocumentType *int `json:"document_type,omitempty"`
+	// StoragePath is the ID of the assigned storage path, if any.
+	StoragePath *int `json:"storage_path,omitempty"`
+	// Tags are the IDs of the assigned tags.
+	Tags []int `json:"tags,omitempty"`
+	// ArchiveSerialNumber is the optional number of the physical document in an archive.
+	ArchiveSerialNumber *int `json:"archive_serial_number,omitempty"`
+	// CustomFields are the values of the assigned custom fields.
+	CustomFields []CustomFieldInstance `json:"custom_fields,omitempty"`
 	// OriginalFileName of the original document, read-only.
 	OriginalFileName string `json:"original_file_name,omitempty"`
 	// ArchivedFileName of the archived document, read-only.
 	// May be empty if no archived document is available.
 	ArchivedFileName string `json:"archived_file_name,omitempty"`
+	// Notes are the comments of users on the document.
+	Notes []Note `json:"notes,omitempty"`
+}
+
+// Note is a comment on a document.
+type Note struct {
+	// ID of the note, read-only.
+	ID int `json:"id"`
+	// Note is the text of the note.
+	Note string `json:"note"`
+	// Created is the timestamp of the note, read-only.
+	Created string `json:"created,omitempty"`
+}
+
+// CustomFieldInstance is the value of a custom field assigned to a document.
+type CustomFieldInstance struct {
+	// Field is the ID of the custom field.
+	Field int `json:"field"`
+	// Value of the custom field, may be nil.
+	Value any `json:"value"`
+}
+
+// CreatedDate parses Document.Created and returns the date part.
+// It returns the zero value if the date cannot be parsed.
+func (d Document) CreatedDate() time.Time {
+	if len(d.Created) < len(time.DateOnly) {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.DateOnly, d.Created[:len(time.DateOnly)])
+	if err != nil {
+		return time.Time{}
+	}
+	return t
 }
 
 func MapToDocumentIDs(docs []Document) []int {
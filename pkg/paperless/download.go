@@ -42,7 +42,7 @@ func (clt *Client) BulkDownload(ctx context.Context, targetFile *os.File, params
 
 	log := logr.FromContextOrDiscard(ctx)
 	log.V(1).Info("Awaiting response")
-	resp, err := clt.HttpClient.Do(req)
+	resp, err := clt.do(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
@@ -72,13 +72,34 @@ func (clt *Client) makeBulkDownloadRequest(ctx context.Context, params BulkDownl
 	}
 	body := bytes.NewReader(marshal)
 
-	path := clt.URL + "/api/documents/bulk_download/"
-	log.V(1).Info("Preparing request", "path", path, "document_ids", params.DocumentIDs)
-	req, err := http.NewRequestWithContext(ctx, "POST", path, body)
+	log.V(1).Info("Preparing bulk download", "document_ids", params.DocumentIDs)
+	return clt.newRequest(ctx, "POST", "/api/documents/bulk_download/", body)
+}
+
+// DownloadDocument downloads a single document and writes the content to the given writer.
+// If original is false, the archived version is downloaded, if available.
+func (clt *Client) DownloadDocument(ctx context.Context, w io.Writer, documentID int, original bool) error {
+	path := fmt.Sprintf("/api/documents/%d/download/", documentID)
+	if original {
+		path += "?original=true"
+	}
+	req, err := clt.newRequest(ctx, "GET", path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("cannot prepare request: %w", err)
+		return err
 	}
-	clt.setAuth(req)
-	req.Header.Set("Content-Type", "application/json")
-	return req, nil
+
+	log := logr.FromContextOrDiscard(ctx)
+	log.V(1).Info("Awaiting response")
+	resp, err := clt.do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed: %s: %s", resp.Status, string(b))
+	}
+	_, err = io.Copy(w, resp.Body)
+	return errors.Wrap(err, "cannot read response body")
 }
@@ -0,0 +1,259 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// MatchingAlgorithm defines how Paperless automatically assigns an entity to new documents.
+type MatchingAlgorithm int
+
+const (
+	MatchNone MatchingAlgorithm = iota
+	MatchAny
+	MatchAll
+	MatchLiteral
+	MatchRegex
+	MatchFuzzy
+	MatchAuto
+)
+
+var matchingAlgorithmNames = []string{"none", "any", "all", "literal", "regex", "fuzzy", "auto"}
+
+// String implements fmt.Stringer.
+func (m MatchingAlgorithm) String() string {
+	if m < 0 || int(m) >= len(matchingAlgorithmNames) {
+		return fmt.Sprintf("unknown(%d)", int(m))
+	}
+	return matchingAlgorithmNames[m]
+}
+
+// MatchingAlgorithmNames returns the names of all matching algorithms.
+func MatchingAlgorithmNames() []string {
+	return append([]string{}, matchingAlgorithmNames...)
+}
+
+// ParseMatchingAlgorithm returns the matching algorithm by its name, e.g. "any".
+func ParseMatchingAlgorithm(name string) (MatchingAlgorithm, error) {
+	for i, algorithm := range matchingAlgorithmNames {
+		if strings.EqualFold(name, algorithm) {
+			return MatchingAlgorithm(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown matching algorithm %q, must be one of [%s]", name, strings.Join(matchingAlgorithmNames, ", "))
+}
+
+// Matching contains the rules for automatically assigning an entity to new documents.
+type Matching struct {
+	// Match is the text or pattern to match, depending on the algorithm.
+	Match string `json:"match,omitempty"`
+	// MatchingAlgorithm defines how Match is applied.
+	MatchingAlgorithm MatchingAlgorithm `json:"matching_algorithm,omitempty"`
+	// IsInsensitive is true if Match is case-insensitive.
+	IsInsensitive bool `json:"is_insensitive,omitempty"`
+}
+
+// Tag is a label that can be assigned to multiple documents.
+type Tag struct {
+	// ID of the tag, read-only.
+	ID int `json:"id"`
+	// Name of the tag.
+	Name string `json:"name"`
+	Matching
+	// Color of the tag as hex value, e.g. "#a6cee3".
+	Color string `json:"color,omitempty"`
+	// IsInboxTag is true if the tag is assigned to all newly consumed documents.
+	IsInboxTag bool `json:"is_inbox_tag,omitempty"`
+	// DocumentCount is the number of documents with this tag, read-only.
+	DocumentCount int `json:"document_count,omitempty"`
+}
+
+// Correspondent is a person or institution that a document originates from.
+type Correspondent struct {
+	// ID of the correspondent, read-only.
+	ID int `json:"id"`
+	// Name of the correspondent.
+	Name string `json:"name"`
+	Matching
+	// DocumentCount is the number of documents with this correspondent, read-only.
+	DocumentCount int `json:"document_count,omitempty"`
+}
+
+// DocumentType classifies a document, e.g. "invoice".
+type DocumentType struct {
+	// ID of the document type, read-only.
+	ID int `json:"id"`
+	// Name of the document type.
+	Name string `json:"name"`
+	Matching
+	// DocumentCount is the number of documents with this type, read-only.
+	DocumentCount int `json:"document_count,omitempty"`
+}
+
+// StoragePath defines where and how documents are stored on the server.
+type StoragePath struct {
+	// ID of the storage path, read-only.
+	ID int `json:"id"`
+	// Name of the storage path.
+	Name string `json:"name"`
+	Matching
+	// Path is the file name format, e.g. "{created_year}/{correspondent}/{title}".
+	Path string `json:"path,omitempty"`
+	// DocumentCount is the number of documents with this storage path, read-only.
+	DocumentCount int `json:"document_count,omitempty"`
+}
+
+// CustomField is an additional field that can be assigned to documents.
+type CustomField struct {
+	// ID of the custom field, read-only.
+	ID int `json:"id"`
+	// Name of the custom field.
+	Name string `json:"name"`
+	// DataType of the custom field, e.g. "string" or "monetary".
+	DataType string `json:"data_type,omitempty"`
+}
+
+// EntityPatch contains the fields of an entity to create or update, keyed by their JSON name.
+// Fields that are not contained are left unchanged or use the server's default.
+type EntityPatch map[string]any
+
+const (
+	tagsPath           = "/api/tags/"
+	correspondentsPath = "/api/correspondents/"
+	documentTypesPath  = "/api/document_types/"
+	storagePathsPath   = "/api/storage_paths/"
+	customFieldsPath   = "/api/custom_fields/"
+)
+
+// QueryTags returns all tags.
+func (clt *Client) QueryTags(ctx context.Context) ([]Tag, error) {
+	return queryAll[Tag](ctx, clt, tagsPath, QueryParams{Ordering: "id", PageSize: 100})
+}
+
+// CreateTag creates a new tag with the given fields.
+func (clt *Client) CreateTag(ctx context.Context, fields EntityPatch) (*Tag, error) {
+	return saveEntity[Tag](ctx, clt, "POST", tagsPath, fields)
+}
+
+// UpdateTag changes the given fields of the tag.
+func (clt *Client) UpdateTag(ctx context.Context, id int, fields EntityPatch) (*Tag, error) {
+	return saveEntity[Tag](ctx, clt, "PATCH", fmt.Sprintf("%s%d/", tagsPath, id), fields)
+}
+
+// DeleteTag deletes the tag.
+func (clt *Client) DeleteTag(ctx context.Context, id int) error {
+	return deleteEntity(ctx, clt, fmt.Sprintf("%s%d/", tagsPath, id))
+}
+
+// QueryCorrespondents returns all correspondents.
+func (clt *Client) QueryCorrespondents(ctx context.Context) ([]Correspondent, error) {
+	return queryAll[Correspondent](ctx, clt, correspondentsPath, QueryParams{Ordering: "id", PageSize: 100})
+}
+
+// CreateCorrespondent creates a new correspondent with the given fields.
+func (clt *Client) CreateCorrespondent(ctx context.Context, fields EntityPatch) (*Correspondent, error) {
+	return saveEntity[Correspondent](ctx, clt, "POST", correspondentsPath, fields)
+}
+
+// UpdateCorrespondent changes the given fields of the correspondent.
+func (clt *Client) UpdateCorrespondent(ctx context.Context, id int, fields EntityPatch) (*Correspondent, error) {
+	return saveEntity[Correspondent](ctx, clt, "PATCH", fmt.Sprintf("%s%d/", correspondentsPath, id), fields)
+}
+
+// DeleteCorrespondent deletes the correspondent.
+func (clt *Client) DeleteCorrespondent(ctx context.Context, id int) error {
+	return deleteEntity(ctx, clt, fmt.Sprintf("%s%d/", correspondentsPath, id))
+}
+
+// QueryDocumentTypes returns all document types.
+func (clt *Client) QueryDocumentTypes(ctx context.Context) ([]DocumentType, error) {
+	return queryAll[DocumentType](ctx, clt, documentTypesPath, QueryParams{Ordering: "id", PageSize: 100})
+}
+
+// CreateDocumentType creates a new document type with the given fields.
+func (clt *Client) CreateDocumentType(ctx context.Context, fields EntityPatch) (*DocumentType, error) {
+	return saveEntity[DocumentType](ctx, clt, "POST", documentTypesPath, fields)
+}
+
+// UpdateDocumentType changes the given fields of the document type.
+func (clt *Client) UpdateDocumentType(ctx context.Context, id int, fields EntityPatch) (*DocumentType, error) {
+	return saveEntity[DocumentType](ctx, clt, "PATCH", fmt.Sprintf("%s%d/", documentTypesPath, id), fields)
+}
+
+// DeleteDocumentType deletes the document type.
+func (clt *Client) DeleteDocumentType(ctx context.Context, id int) error {
+	return deleteEntity(ctx, clt, fmt.Sprintf("%s%d/", documentTypesPath, id))
+}
+
+// QueryStoragePaths returns all storage paths.
+func (clt *Client) QueryStoragePaths(ctx context.Context) ([]StoragePath, error) {
+	return queryAll[StoragePath](ctx, clt, storagePathsPath, QueryParams{Ordering: "id", PageSize: 100})
+}
+
+// CreateStoragePath creates a new storage path with the given fields.
+func (clt *Client) CreateStoragePath(ctx context.Context, fields EntityPatch) (*StoragePath, error) {
+	return saveEntity[StoragePath](ctx, clt, "POST", storagePathsPath, fields)
+}
+
+// UpdateStoragePath changes the given fields of the storage path.
+func (clt *Client) UpdateStoragePath(ctx context.Context, id int, fields EntityPatch) (*StoragePath, error) {
+	return saveEntity[StoragePath](ctx, clt, "PATCH", fmt.Sprintf("%s%d/", storagePathsPath, id), fields)
+}
+
+// DeleteStoragePath deletes the storage path.
+func (clt *Client) DeleteStoragePath(ctx context.Context, id int) error {
+	return deleteEntity(ctx, clt, fmt.Sprintf("%s%d/", storagePathsPath, id))
+}
+
+// QueryCustomFields returns all custom fields.
+func (clt *Client) QueryCustomFields(ctx context.Context) ([]CustomField, error) {
+	return queryAll[CustomField](ctx, clt, customFieldsPath, QueryParams{Ordering: "id", PageSize: 100})
+}
+
+// CreateCustomField creates a new custom field with the given fields.
+func (clt *Client) CreateCustomField(ctx context.Context, fields EntityPatch) (*CustomField, error) {
+	return saveEntity[CustomField](ctx, clt, "POST", customFieldsPath, fields)
+}
+
+// UpdateCustomField changes the given fields of the custom field.
+func (clt *Client) UpdateCustomField(ctx context.Context, id int, fields EntityPatch) (*CustomField, error) {
+	return saveEntity[CustomField](ctx, clt, "PATCH", fmt.Sprintf("%s%d/", customFieldsPath, id), fields)
+}
+
+// DeleteCustomField deletes the custom field.
+func (clt *Client) DeleteCustomField(ctx context.Context, id int) error {
+	return deleteEntity(ctx, clt, fmt.Sprintf("%s%d/", customFieldsPath, id))
+}
+
+// saveEntity creates (POST) or updates (PATCH) the entity at the given path.
+func saveEntity[T any](ctx context.Context, clt *Client, method, path string, fields EntityPatch) (*T, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	marshal, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize to JSON: %w", err)
+	}
+	log.V(1).Info("Saving entity", "path", path, "fields", string(marshal))
+	req, err := clt.newRequest(ctx, method, path, bytes.NewReader(marshal))
+	if err != nil {
+		return nil, err
+	}
+	entity := new(T)
+	if err := clt.doJSON(req, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func deleteEntity(ctx context.Context, clt *Client, path string) error {
+	req, err := clt.newRequest(ctx, "DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	return clt.doJSON(req, nil)
+}
@@ -0,0 +1,41 @@
+package paperless
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMatchingAlgorithm(t *testing.T) {
+	tests := map[string]struct {
+		givenName         string
+		expectedAlgorithm MatchingAlgorithm
+		expectedError     string
+	}{
+		"None": {
+			givenName:         "none",
+			expectedAlgorithm: MatchNone,
+		},
+		"CaseInsensitive": {
+			givenName:         "Regex",
+			expectedAlgorithm: MatchRegex,
+		},
+		"Unknown": {
+			givenName:     "magic",
+			expectedError: `unknown matching algorithm "magic", must be one of [none, any, all, literal, regex, fuzzy, auto]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseMatchingAlgorithm(tt.givenName)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAlgorithm, result)
+			assert.Equal(t, strings.ToLower(tt.givenName), result.String())
+		})
+	}
+}
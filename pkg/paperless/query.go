@@ -2,40 +2,39 @@ package paperless
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
-
-	"github.com/go-logr/logr"
 )
 
 type QueryParams struct {
 	TruncateContent bool   `param:"truncate_content"`
 	Ordering        string `param:"ordering"`
 	PageSize        int64  `param:"page_size"`
-	page            int64  `param:"page"`
+	// Query is a full text search query, e.g. "tag:invoice correspondent:bank".
+	Query string `param:"query"`
+	// Checksum filters documents by the MD5 checksum of their original file.
+	Checksum string `param:"checksum__iexact"`
+	page     int64  `param:"page"`
 }
 
-type QueryResult struct {
-	Results []Document `json:"results,omitempty"`
-	Next    string     `json:"next,omitempty"`
+type QueryResult[T any] struct {
+	Results []T    `json:"results,omitempty"`
+	Next    string `json:"next,omitempty"`
 }
 
 // NextPage returns the next page number for pagination.
 // It returns 1 if QueryResult.Next is empty (first page), or 0 if there's an error parsing QueryResult.Next.
-func (r QueryResult) NextPage() int64 {
+func (r QueryResult[T]) NextPage() int64 {
 	if r.Next == "" {
 		return 1 // first page
 	}
-	values, err := url.ParseQuery(r.Next)
+	next, err := url.Parse(r.Next)
 	if err != nil {
 		return 0
 	}
-	raw := values.Get("page")
+	raw := next.Query().Get("page")
 	page, err := strconv.ParseInt(raw, 10, 64)
 	if err != nil {
 		return 0
@@ -43,68 +42,41 @@ func (r QueryResult) NextPage() int64 {
 	return page
 }
 
+// QueryDocuments returns all documents by following the pagination of the API.
 func (clt *Client) QueryDocuments(ctx context.Context, params QueryParams) ([]Document, error) {
-	documents := make([]Document, 0)
+	return queryAll[Document](ctx, clt, "/api/documents/", params)
+}
+
+// queryAll fetches all pages of the given list endpoint.
+func queryAll[T any](ctx context.Context, clt *Client, path string, params QueryParams) ([]T, error) {
+	items := make([]T, 0)
 	params.page = 1
 	for i := int64(0); i < params.page; i++ {
-		result, err := clt.queryDocumentsInPage(ctx, params)
+		result, err := queryPage[T](ctx, clt, path, params)
 		if err != nil {
 			return nil, err
 		}
 		params.page = result.NextPage()
-		documents = append(documents, result.Results...)
+		items = append(items, result.Results...)
 	}
-	return documents, nil
+	return items, nil
 }
 
-func (clt *Client) makeQueryRequest(ctx context.Context, params QueryParams) (*http.Request, error) {
-	log := logr.FromContextOrDiscard(ctx)
-
+func queryPage[T any](ctx context.Context, clt *Client, path string, params QueryParams) (*QueryResult[T], error) {
 	values := paramsToValues(params)
-
-	path := clt.URL + "/api/documents/?" + values.Encode()
-	log.V(1).Info("Preparing request", "path", path)
-	req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
-	if err != nil {
-		return nil, fmt.Errorf("cannot prepare request: %w", err)
-	}
-	clt.setAuth(req)
-	req.Header.Set("Content-Type", "application/json")
-	return req, nil
-}
-
-func (clt *Client) queryDocumentsInPage(ctx context.Context, params QueryParams) (*QueryResult, error) {
-	req, err := clt.makeQueryRequest(ctx, params)
+	req, err := clt.newRequest(ctx, "GET", path+"?"+values.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-
-	log := logr.FromContextOrDiscard(ctx)
-	log.V(1).Info("Awaiting response")
-	resp, err := clt.HttpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	b, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("cannot read body: %w", err)
-	}
-	log.V(2).Info("Read response", "body", string(b))
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed: %s: %s", resp.Status, string(b))
-	}
-
-	result := QueryResult{}
-	parseErr := json.Unmarshal(b, &result)
-	if parseErr != nil {
-		return nil, fmt.Errorf("cannot parse JSON: %w", parseErr)
+	result := QueryResult[T]{}
+	if err := clt.doJSON(req, &result); err != nil {
+		return nil, err
 	}
-	log.V(1).Info("Parsed response", "result", result)
 	return &result, nil
 }
 
+// paramsToValues converts the params to URL query values.
+// Fields with zero values are omitted.
 func paramsToValues(params QueryParams) url.Values {
 	values := url.Values{}
 	typ := reflect.TypeOf(params)
@@ -124,6 +96,9 @@ func paramsToValues(params QueryParams) url.Values {
 		default:
 			panic(fmt.Errorf("not implemented type: %s", field.Kind()))
 		}
+		if field.IsZero() {
+			continue
+		}
 		values.Set(tag, paramValue)
 	}
 	return values
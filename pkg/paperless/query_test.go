@@ -0,0 +1,59 @@
+package paperless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryResult_NextPage(t *testing.T) {
+	tests := map[string]struct {
+		givenNext    string
+		expectedPage int64
+	}{
+		"FirstPage": {
+			givenNext:    "",
+			expectedPage: 1,
+		},
+		"PageIsFirstParameter": {
+			givenNext:    "http://localhost:8000/api/tags/?page=2&page_size=100",
+			expectedPage: 2,
+		},
+		"PageIsLaterParameter": {
+			givenNext:    "http://localhost:8000/api/documents/?ordering=id&page=3",
+			expectedPage: 3,
+		},
+		"InvalidPage": {
+			givenNext:    "http://localhost:8000/api/documents/?page=last",
+			expectedPage: 0,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := QueryResult[Document]{Next: tt.givenNext}
+			assert.Equal(t, tt.expectedPage, result.NextPage())
+		})
+	}
+}
+
+func TestParamsToValues(t *testing.T) {
+	tests := map[string]struct {
+		givenParams   QueryParams
+		expectedQuery string
+	}{
+		"ZeroValuesOmitted": {
+			givenParams:   QueryParams{},
+			expectedQuery: "",
+		},
+		"AllValues": {
+			givenParams:   QueryParams{TruncateContent: true, Ordering: "id", PageSize: 100, Query: "tag:invoice", Checksum: "abc", page: 2},
+			expectedQuery: "checksum__iexact=abc&ordering=id&page=2&page_size=100&query=tag%3Ainvoice&truncate_content=true",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := paramsToValues(tt.givenParams)
+			assert.Equal(t, tt.expectedQuery, result.Encode())
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package paperless
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Resolver looks up the IDs of tags, correspondents and other entities by their name.
+// Each list is fetched at most once from the server.
+type Resolver struct {
+	clt            *Client
+	tags           []Tag
+	correspondents []Correspondent
+	documentTypes  []DocumentType
+	storagePaths   []StoragePath
+	customFields   []CustomField
+}
+
+// NewResolver returns a new Resolver using the given client.
+func NewResolver(clt *Client) *Resolver {
+	return &Resolver{clt: clt}
+}
+
+// TagID returns the ID of the tag with the given name.
+// If no name matches and nameOrID is a number, it is taken as ID of an existing entity.
+func (r *Resolver) TagID(ctx context.Context, nameOrID string) (int, error) {
+	return resolveID(ctx, "tag", nameOrID, &r.tags, r.clt.QueryTags, func(e Tag) (int, string) { return e.ID, e.Name })
+}
+
+// TagIDs returns the IDs of the tags with the given names.
+func (r *Resolver) TagIDs(ctx context.Context, namesOrIDs []string) ([]int, error) {
+	ids := make([]int, len(namesOrIDs))
+	for i, name := range namesOrIDs {
+		id, err := r.TagID(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// CorrespondentID returns the ID of the correspondent with the given name.
+// If no name matches and nameOrID is a number, it is taken as ID of an existing entity.
+func (r *Resolver) CorrespondentID(ctx context.Context, nameOrID string) (int, error) {
+	return resolveID(ctx, "correspondent", nameOrID, &r.correspondents, r.clt.QueryCorrespondents, func(e Correspondent) (int, string) { return e.ID, e.Name })
+}
+
+// DocumentTypeID returns the ID of the document type with the given name.
+// If no name matches and nameOrID is a number, it is taken as ID of an existing entity.
+func (r *Resolver) DocumentTypeID(ctx context.Context, nameOrID string) (int, error) {
+	return resolveID(ctx, "document type", nameOrID, &r.documentTypes, r.clt.QueryDocumentTypes, func(e DocumentType) (int, string) { return e.ID, e.Name })
+}
+
+// StoragePathID returns the ID of the storage path with the given name.
+// If no name matches and nameOrID is a number, it is taken as ID of an existing entity.
+func (r *Resolver) StoragePathID(ctx context.Context, nameOrID string) (int, error) {
+	return resolveID(ctx, "storage path", nameOrID, &r.storagePaths, r.clt.QueryStoragePaths, func(e StoragePath) (int, string) { return e.ID, e.Name })
+}
+
+// CustomFieldID returns the ID of the custom field with the given name.
+// If no name matches and nameOrID is a number, it is taken as ID of an existing entity.
+func (r *Resolver) CustomFieldID(ctx context.Context, nameOrID string) (int, error) {
+	return resolveID(ctx, "custom field", nameOrID, &r.customFields, r.clt.QueryCustomFields, func(e CustomField) (int, string) { return e.ID, e.Name })
+}
+
+// resolveID returns the ID of the entity whose name equals nameOrID, preferring an exact match over a case-insensitive one.
+// Names take precedence over IDs, as names may be numbers too, e.g. a tag "2024".
+func resolveID[T any](ctx context.Context, kind, nameOrID string, cache *[]T, query func(ctx context.Context) ([]T, error), idAndName func(T) (int, string)) (int, error) {
+	if *cache == nil {
+		entities, err := query(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("cannot query %ss: %w", kind, err)
+		}
+		*cache = entities
+	}
+	for _, entity := range *cache {
+		if id, name := idAndName(entity); name == nameOrID {
+			return id, nil
+		}
+	}
+	for _, entity := range *cache {
+		if id, name := idAndName(entity); strings.EqualFold(name, nameOrID) {
+			return id, nil
+		}
+	}
+	if wantedID, err := strconv.Atoi(nameOrID); err == nil {
+		for _, entity := range *cache {
+			if id, _ := idAndName(entity); id == wantedID {
+				return id, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%s not found: %s", kind, nameOrID)
+}
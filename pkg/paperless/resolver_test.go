@@ -0,0 +1,59 @@
+package paperless
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_TagID(t *testing.T) {
+	r := &Resolver{tags: []Tag{
+		{ID: 1, Name: "Invoice"},
+		{ID: 2, Name: "2024"},
+		{ID: 3, Name: "invoice"},
+		{ID: 2024, Name: "Archive"},
+		{ID: 5, Name: "Receipt"},
+	}}
+	tests := map[string]struct {
+		givenNameOrID string
+		expectedID    int
+		expectedError string
+	}{
+		"ExactName": {
+			givenNameOrID: "invoice",
+			expectedID:    3,
+		},
+		"CaseInsensitiveName": {
+			givenNameOrID: "RECEIPT",
+			expectedID:    5,
+		},
+		"NumericNameBeforeID": {
+			givenNameOrID: "2024",
+			expectedID:    2,
+		},
+		"ID": {
+			givenNameOrID: "5",
+			expectedID:    5,
+		},
+		"UnknownID": {
+			givenNameOrID: "42",
+			expectedError: "tag not found: 42",
+		},
+		"UnknownName": {
+			givenNameOrID: "Contract",
+			expectedError: "tag not found: Contract",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, err := r.TagID(context.Background(), tt.givenNameOrID)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, id)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package paperless
+
+import (
+	"context"
+)
+
+// Statistics contains basic numbers of the server.
+type Statistics struct {
+	DocumentsTotal int `json:"documents_total"`
+	DocumentsInbox int `json:"documents_inbox"`
+}
+
+// GetStatistics returns the statistics of the server.
+func (clt *Client) GetStatistics(ctx context.Context) (*Statistics, error) {
+	req, err := clt.newRequest(ctx, "GET", "/api/statistics/", nil)
+	if err != nil {
+		return nil, err
+	}
+	result := &Statistics{}
+	if err := clt.doJSON(req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package paperless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskStatus is the state of a task on the server.
+type TaskStatus string
+
+const (
+	TaskPending TaskStatus = "PENDING"
+	TaskStarted TaskStatus = "STARTED"
+	TaskSuccess TaskStatus = "SUCCESS"
+	TaskFailure TaskStatus = "FAILURE"
+	TaskRetry   TaskStatus = "RETRY"
+	TaskRevoked TaskStatus = "REVOKED"
+)
+
+// Task is an asynchronous job on the server, e.g. consuming an uploaded document.
+type Task struct {
+	// ID of the task in the database, read-only.
+	ID int `json:"id"`
+	// TaskID is the UUID of the task as returned by UploadDocument.
+	TaskID string `json:"task_id"`
+	// Status of the task.
+	Status TaskStatus `json:"status"`
+	// Result is the message of the finished task, e.g. the reason of a failure.
+	Result string `json:"result"`
+	// RelatedDocument is the ID of the consumed document, if any.
+	// Depending on the version, the API returns it as string or number.
+	RelatedDocument json.Number `json:"related_document"`
+}
+
+// Done returns true if the task has finished, successfully or not.
+func (t Task) Done() bool {
+	return t.Status == TaskSuccess || t.Status == TaskFailure || t.Status == TaskRevoked
+}
+
+// IsDuplicate returns true if Paperless refused to consume the document, because it already exists.
+func (t Task) IsDuplicate() bool {
+	return t.Status == TaskFailure && strings.Contains(strings.ToLower(t.Result), "duplicate")
+}
+
+// DocumentID returns the ID of the related document.
+// It returns false if there is no related document.
+func (t Task) DocumentID() (int, bool) {
+	id, err := strconv.Atoi(t.RelatedDocument.String())
+	return id, err == nil
+}
+
+// GetTask returns the task with the given UUID.
+func (clt *Client) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	req, err := clt.newRequest(ctx, "GET", "/api/tasks/?task_id="+url.QueryEscape(taskID), nil)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := clt.doJSON(req, &tasks); err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("task %q not found", taskID)
+	}
+	return &tasks[0], nil
+}
+
+// QueryTasks returns the tasks that haven't been acknowledged in the UI yet.
+func (clt *Client) QueryTasks(ctx context.Context) ([]Task, error) {
+	req, err := clt.newRequest(ctx, "GET", "/api/tasks/", nil)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0)
+	if err := clt.doJSON(req, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// WaitForTask polls the task with the given UUID until it has finished or the context is done.
+// An error is returned if the task failed.
+// Tasks that are not yet known to the server are treated as pending.
+func (clt *Client) WaitForTask(ctx context.Context, taskID string, interval time.Duration) (*Task, error) {
+	for {
+		task, err := clt.GetTask(ctx, taskID)
+		if err == nil && task.Done() {
+			if task.Status != TaskSuccess {
+				return task, fmt.Errorf("task %s: %s", task.Status, task.Result)
+			}
+			return task, nil
+		}
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ctx.Err(), err)
+			}
+			return nil, fmt.Errorf("task %q not finished: %w", taskID, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
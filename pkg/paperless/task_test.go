@@ -0,0 +1,64 @@
+package paperless
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_DocumentID(t *testing.T) {
+	tests := map[string]struct {
+		givenJSON     string
+		expectedID    int
+		expectedFound bool
+	}{
+		"String": {
+			givenJSON:     `{"status": "SUCCESS", "related_document": "12"}`,
+			expectedID:    12,
+			expectedFound: true,
+		},
+		"Number": {
+			givenJSON:     `{"status": "SUCCESS", "related_document": 12}`,
+			expectedID:    12,
+			expectedFound: true,
+		},
+		"Null": {
+			givenJSON:     `{"status": "FAILURE", "related_document": null}`,
+			expectedFound: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			task := Task{}
+			require.NoError(t, json.Unmarshal([]byte(tt.givenJSON), &task))
+			id, found := task.DocumentID()
+			assert.Equal(t, tt.expectedFound, found)
+			assert.Equal(t, tt.expectedID, id)
+		})
+	}
+}
+
+func TestTask_IsDuplicate(t *testing.T) {
+	tests := map[string]struct {
+		givenTask         Task
+		expectedDuplicate bool
+	}{
+		"Duplicate": {
+			givenTask:         Task{Status: TaskFailure, Result: "Not consuming invoice.pdf: It is a duplicate of Invoice (#12)."},
+			expectedDuplicate: true,
+		},
+		"OtherFailure": {
+			givenTask: Task{Status: TaskFailure, Result: "Unsupported mime type"},
+		},
+		"Success": {
+			givenTask: Task{Status: TaskSuccess, Result: "Success. New document id 12 created"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedDuplicate, tt.givenTask.IsDuplicate())
+		})
+	}
+}
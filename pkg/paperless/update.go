@@ -0,0 +1,110 @@
+package paperless
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-logr/logr"
+)
+
+// DocumentPatch contains the fields of a document to update, keyed by their JSON name.
+// Fields that are not contained are left unchanged, nil values unset the field.
+type DocumentPatch map[string]any
+
+// forAPIVersion returns the patch with the field names of the given API version.
+// Since version 9, "created" is a date and replaces "created_date".
+func (p DocumentPatch) forAPIVersion(version int) DocumentPatch {
+	created, found := p["created_date"]
+	if !found || version < 9 {
+		return p
+	}
+	result := make(DocumentPatch, len(p))
+	for k, v := range p {
+		result[k] = v
+	}
+	delete(result, "created_date")
+	result["created"] = created
+	return result
+}
+
+// GetDocument returns the document with the given ID.
+func (clt *Client) GetDocument(ctx context.Context, id int) (*Document, error) {
+	req, err := clt.newRequest(ctx, "GET", fmt.Sprintf("/api/documents/%d/", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{}
+	if err := clt.doJSON(req, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// DocumentMetadata contains technical details of the files of a document.
+type DocumentMetadata struct {
+	// OriginalChecksum is the MD5 checksum of the original file.
+	OriginalChecksum string `json:"original_checksum"`
+	// OriginalSize is the size of the original file in bytes.
+	OriginalSize int64 `json:"original_size"`
+	// OriginalMimeType is the MIME type of the original file.
+	OriginalMimeType string `json:"original_mime_type"`
+	// ArchiveChecksum is the MD5 checksum of the archived file, if any.
+	ArchiveChecksum string `json:"archive_checksum,omitempty"`
+}
+
+// GetDocumentMetadata returns the file metadata of the document with the given ID.
+func (clt *Client) GetDocumentMetadata(ctx context.Context, id int) (*DocumentMetadata, error) {
+	req, err := clt.newRequest(ctx, "GET", fmt.Sprintf("/api/documents/%d/metadata/", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	metadata := &DocumentMetadata{}
+	if err := clt.doJSON(req, metadata); err != nil {
+		return nil, err
+	}
+	return metadata, nil
+}
+
+// UpdateDocument changes the fields given in patch and returns the updated document.
+func (clt *Client) UpdateDocument(ctx context.Context, id int, patch DocumentPatch) (*Document, error) {
+	log := logr.FromContextOrDiscard(ctx)
+
+	marshal, err := json.Marshal(patch.forAPIVersion(clt.APIVersion()))
+	if err != nil {
+		return nil, fmt.Errorf("cannot serialize to JSON: %w", err)
+	}
+	log.V(1).Info("Updating document", "id", id, "patch", string(marshal))
+	req, err := clt.newRequest(ctx, "PATCH", fmt.Sprintf("/api/documents/%d/", id), bytes.NewReader(marshal))
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{}
+	if err := clt.doJSON(req, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// DeleteDocument deletes the document with the given ID.
+func (clt *Client) DeleteDocument(ctx context.Context, id int) error {
+	req, err := clt.newRequest(ctx, "DELETE", fmt.Sprintf("/api/documents/%d/", id), nil)
+	if err != nil {
+		return err
+	}
+	return clt.doJSON(req, nil)
+}
+
+// AddNote adds a note to the document with the given ID.
+func (clt *Client) AddNote(ctx context.Context, id int, note string) error {
+	marshal, err := json.Marshal(map[string]string{"note": note})
+	if err != nil {
+		return fmt.Errorf("cannot serialize to JSON: %w", err)
+	}
+	req, err := clt.newRequest(ctx, "POST", fmt.Sprintf("/api/documents/%d/notes/", id), bytes.NewReader(marshal))
+	if err != nil {
+		return err
+	}
+	return clt.doJSON(req, nil)
+}
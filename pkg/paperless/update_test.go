@@ -0,0 +1,37 @@
+package paperless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentPatch_forAPIVersion(t *testing.T) {
+	tests := map[string]struct {
+		givenPatch    DocumentPatch
+		givenVersion  int
+		expectedPatch DocumentPatch
+	}{
+		"CreatedDateBeforeVersion9": {
+			givenPatch:    DocumentPatch{"created_date": "2024-01-31", "title": "Invoice"},
+			givenVersion:  8,
+			expectedPatch: DocumentPatch{"created_date": "2024-01-31", "title": "Invoice"},
+		},
+		"CreatedSinceVersion9": {
+			givenPatch:    DocumentPatch{"created_date": "2024-01-31", "title": "Invoice"},
+			givenVersion:  9,
+			expectedPatch: DocumentPatch{"created": "2024-01-31", "title": "Invoice"},
+		},
+		"NoCreatedDate": {
+			givenPatch:    DocumentPatch{"title": "Invoice"},
+			givenVersion:  9,
+			expectedPatch: DocumentPatch{"title": "Invoice"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.givenPatch.forAPIVersion(tt.givenVersion)
+			assert.Equal(t, tt.expectedPatch, result)
+		})
+	}
+}
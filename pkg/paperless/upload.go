@@ -3,12 +3,14 @@ package paperless
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -23,24 +25,37 @@ type UploadParams struct {
 }
 
 func (clt *Client) Upload(ctx context.Context, filePath string, params UploadParams) error {
+	_, err := clt.UploadDocument(ctx, filePath, params)
+	return err
+}
+
+// UploadDocument uploads the file and returns the ID of the task that consumes it on the server.
+// Use WaitForTask to get the ID of the created document.
+func (clt *Client) UploadDocument(ctx context.Context, filePath string, params UploadParams) (string, error) {
 	req, err := clt.makeFileUploadRequest(ctx, filePath, params)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	resp, err := clt.HttpClient.Do(req)
+	resp, err := clt.do(req)
 	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
+		return "", fmt.Errorf("request failed: %w", err)
 	}
+	defer resp.Body.Close()
 	body, _ := io.ReadAll(resp.Body)
 	errMessage := string(body)
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return nil
+		var taskID string
+		if parseErr := json.Unmarshal(body, &taskID); parseErr != nil {
+			// older versions respond with plain "OK"
+			return strings.Trim(string(body), `"`), nil
+		}
+		return taskID, nil
 	case http.StatusUnauthorized:
-		return fmt.Errorf("unauthorized")
+		return "", fmt.Errorf("unauthorized")
 	default:
-		return fmt.Errorf("request failed with status code %d: %v", resp.StatusCode, errMessage)
+		return "", fmt.Errorf("request failed with status code %d: %v", resp.StatusCode, errMessage)
 	}
 }
 
@@ -0,0 +1,128 @@
+package paperless
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinAPIVersion is the oldest API version supported by the Client.
+	// Version 2 introduced the hex colors of tags.
+	MinAPIVersion = 2
+	// MaxAPIVersion is the API version requested by the Client, unless the server only supports older versions.
+	// Version 9 changed "created" of documents to a date.
+	MaxAPIVersion = 9
+)
+
+// ServerVersion is the version of the server as reported in the response headers.
+// The server only sends the headers to authenticated users.
+type ServerVersion struct {
+	// APIVersion is the highest API version supported by the server, from the X-Api-Version header.
+	// It's 0 if unknown.
+	APIVersion int
+	// Version is the version of Paperless-ngx, from the X-Version header.
+	Version string
+}
+
+// UnsupportedVersionError is returned if the server doesn't support the API versions of the Client.
+type UnsupportedVersionError struct {
+	// Requested is the API version requested by the Client.
+	Requested int
+	// Supported is the highest API version supported by the server, 0 if unknown.
+	Supported int
+	// Message is the response of the server.
+	Message string
+}
+
+func (e *UnsupportedVersionError) Error() string {
+	if e.Supported > 0 && e.Supported < MinAPIVersion {
+		return fmt.Sprintf("server supports API version %d, but at least version %d is required: please upgrade Paperless-ngx", e.Supported, MinAPIVersion)
+	}
+	return fmt.Sprintf("server doesn't support API version %d: %s", e.Requested, e.Message)
+}
+
+// ServerVersion returns the version of the server as seen in the last response.
+// It's empty if no request has been made yet.
+func (clt *Client) ServerVersion() ServerVersion {
+	clt.versionMutex.Lock()
+	defer clt.versionMutex.Unlock()
+	return clt.serverVersion
+}
+
+// APIVersion returns the API version used for requests.
+// It's MaxAPIVersion until the server reports an older version.
+func (clt *Client) APIVersion() int {
+	clt.versionMutex.Lock()
+	defer clt.versionMutex.Unlock()
+	return clt.apiVersion
+}
+
+// recordServerVersion saves the version of the server from the response headers.
+// The API version for subsequent requests is lowered if the server doesn't support the current one.
+func (clt *Client) recordServerVersion(header http.Header) {
+	apiVersion, err := strconv.Atoi(header.Get("X-Api-Version"))
+	if err != nil {
+		return
+	}
+	clt.versionMutex.Lock()
+	defer clt.versionMutex.Unlock()
+	clt.serverVersion = ServerVersion{APIVersion: apiVersion, Version: header.Get("X-Version")}
+	if apiVersion >= MinAPIVersion && apiVersion < clt.apiVersion {
+		clt.apiVersion = apiVersion
+	}
+}
+
+// do sends the request with the negotiated API version.
+// If the server rejects the version, the request is repeated once with the highest version supported by the server,
+// or with MinAPIVersion if the server doesn't tell, e.g. for unauthenticated requests.
+// An UnsupportedVersionError is returned if the server doesn't support any version of the Client.
+func (clt *Client) do(req *http.Request) (*http.Response, error) {
+	version := clt.APIVersion()
+	resp, err := clt.doWithVersion(req, version)
+	if err != nil || resp.StatusCode != http.StatusNotAcceptable {
+		return resp, err
+	}
+	versionErr := newUnsupportedVersionError(resp, version, clt.ServerVersion().APIVersion)
+	retryVersion := versionErr.Supported
+	if retryVersion == 0 {
+		retryVersion = MinAPIVersion
+	}
+	if retryVersion < MinAPIVersion || retryVersion >= version {
+		return nil, versionErr
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return nil, versionErr
+		}
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	resp, err = clt.doWithVersion(retry, retryVersion)
+	if err != nil || resp.StatusCode != http.StatusNotAcceptable {
+		return resp, err
+	}
+	return nil, newUnsupportedVersionError(resp, retryVersion, clt.ServerVersion().APIVersion)
+}
+
+func (clt *Client) doWithVersion(req *http.Request, version int) (*http.Response, error) {
+	req.Header.Set("Accept", fmt.Sprintf("application/json; version=%d", version))
+	resp, err := clt.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	clt.recordServerVersion(resp.Header)
+	return resp, nil
+}
+
+// newUnsupportedVersionError consumes the body of the given response.
+func newUnsupportedVersionError(resp *http.Response, requested, supported int) *UnsupportedVersionError {
+	b, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	return &UnsupportedVersionError{Requested: requested, Supported: supported, Message: strings.TrimSpace(string(b))}
+}
@@ -0,0 +1,118 @@
+package paperless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_recordServerVersion(t *testing.T) {
+	tests := map[string]struct {
+		givenHeaders       map[string]string
+		expectedVersion    ServerVersion
+		expectedAPIVersion int
+	}{
+		"OlderServer": {
+			givenHeaders:       map[string]string{"X-Api-Version": "5", "X-Version": "2.3.0"},
+			expectedVersion:    ServerVersion{APIVersion: 5, Version: "2.3.0"},
+			expectedAPIVersion: 5,
+		},
+		"NewerServer": {
+			givenHeaders:       map[string]string{"X-Api-Version": "42", "X-Version": "9.0.0"},
+			expectedVersion:    ServerVersion{APIVersion: 42, Version: "9.0.0"},
+			expectedAPIVersion: MaxAPIVersion,
+		},
+		"UnsupportedServer": {
+			givenHeaders:       map[string]string{"X-Api-Version": "1", "X-Version": "1.0.0"},
+			expectedVersion:    ServerVersion{APIVersion: 1, Version: "1.0.0"},
+			expectedAPIVersion: MaxAPIVersion,
+		},
+		"Unauthenticated": {
+			givenHeaders:       map[string]string{},
+			expectedVersion:    ServerVersion{},
+			expectedAPIVersion: MaxAPIVersion,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range tt.givenHeaders {
+				header.Set(k, v)
+			}
+			clt := NewClient("", "", "")
+			clt.recordServerVersion(header)
+			assert.Equal(t, tt.expectedVersion, clt.ServerVersion())
+			assert.Equal(t, tt.expectedAPIVersion, clt.APIVersion())
+		})
+	}
+}
+
+func TestClient_do(t *testing.T) {
+	tests := map[string]struct {
+		givenServerVersion string
+		// givenAccepted is the API version accepted by the server, defaults to givenServerVersion.
+		givenAccepted      string
+		expectedAPIVersion int
+		expectedError      string
+	}{
+		"Supported": {
+			givenServerVersion: strconv.Itoa(MaxAPIVersion),
+			expectedAPIVersion: MaxAPIVersion,
+		},
+		"Downgraded": {
+			givenServerVersion: "5",
+			expectedAPIVersion: 5,
+		},
+		"TooOld": {
+			givenServerVersion: "1",
+			expectedError:      "request failed: server supports API version 1, but at least version 2 is required: please upgrade Paperless-ngx",
+		},
+		"UnknownFallback": {
+			givenServerVersion: "",
+			givenAccepted:      "2",
+			expectedAPIVersion: MaxAPIVersion,
+		},
+		"UnknownTooOld": {
+			givenServerVersion: "",
+			givenAccepted:      "1",
+			expectedError:      `request failed: server doesn't support API version 2: {"detail": "Invalid version in \"Accept\" header."}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Api-Version", tt.givenServerVersion)
+				accepted := tt.givenServerVersion
+				if tt.givenAccepted != "" {
+					accepted = tt.givenAccepted
+				}
+				if r.Header.Get("Accept") != "application/json; version="+accepted {
+					w.WriteHeader(http.StatusNotAcceptable)
+					_, _ = w.Write([]byte(`{"detail": "Invalid version in \"Accept\" header."}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"title": "` + r.Method + `"}`))
+			}))
+			defer server.Close()
+
+			clt := NewClient(server.URL, "", "token")
+			req, err := clt.newRequest(context.Background(), "PATCH", "/api/documents/1/", strings.NewReader(`{}`))
+			require.NoError(t, err)
+			doc := &Document{}
+			err = clt.doJSON(req, doc)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "PATCH", doc.Title)
+			assert.Equal(t, tt.expectedAPIVersion, clt.APIVersion())
+		})
+	}
+}
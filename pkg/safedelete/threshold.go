@@ -0,0 +1,50 @@
+package safedelete
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Threshold is the maximum amount of deletions, either absolute or relative to a total.
+type Threshold struct {
+	value   float64
+	percent bool
+}
+
+// ParseThreshold parses a threshold like "10" (absolute) or "10%" (relative).
+func ParseThreshold(s string) (Threshold, error) {
+	raw, isPercent := strings.CutSuffix(strings.TrimSpace(s), "%")
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: must be a positive number or percentage", s)
+	}
+	if isPercent && value > 100 {
+		return Threshold{}, fmt.Errorf("invalid threshold %q: percentage cannot be larger than 100%%", s)
+	}
+	return Threshold{value: value, percent: isPercent}, nil
+}
+
+// Exceeded returns true if the given count is larger than the threshold.
+// The total is used for relative thresholds.
+func (t Threshold) Exceeded(count, total int) bool {
+	if count == 0 {
+		return false
+	}
+	if !t.percent {
+		return float64(count) > t.value
+	}
+	if total == 0 {
+		return true
+	}
+	return float64(count)*100/float64(total) > t.value
+}
+
+// String implements fmt.Stringer.
+func (t Threshold) String() string {
+	value := strconv.FormatFloat(t.value, 'f', -1, 64)
+	if t.percent {
+		return value + "%"
+	}
+	return value
+}
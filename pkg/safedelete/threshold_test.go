@@ -0,0 +1,100 @@
+package safedelete
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThreshold(t *testing.T) {
+	tests := map[string]struct {
+		givenValue     string
+		expectedString string
+		expectedError  string
+	}{
+		"Absolute": {
+			givenValue:     "10",
+			expectedString: "10",
+		},
+		"Percentage": {
+			givenValue:     "12.5%",
+			expectedString: "12.5%",
+		},
+		"Negative": {
+			givenValue:    "-1",
+			expectedError: `invalid threshold "-1": must be a positive number or percentage`,
+		},
+		"PercentageTooLarge": {
+			givenValue:    "101%",
+			expectedError: `invalid threshold "101%": percentage cannot be larger than 100%`,
+		},
+		"NotANumber": {
+			givenValue:    "many",
+			expectedError: `invalid threshold "many": must be a positive number or percentage`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseThreshold(tt.givenValue)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedString, result.String())
+		})
+	}
+}
+
+func TestThreshold_Exceeded(t *testing.T) {
+	tests := map[string]struct {
+		givenThreshold string
+		givenCount     int
+		givenTotal     int
+		expectedResult bool
+	}{
+		"NoDeletions": {
+			givenThreshold: "0",
+			givenCount:     0,
+			givenTotal:     0,
+			expectedResult: false,
+		},
+		"Absolute_NotExceeded": {
+			givenThreshold: "10",
+			givenCount:     10,
+			givenTotal:     20,
+			expectedResult: false,
+		},
+		"Absolute_Exceeded": {
+			givenThreshold: "10",
+			givenCount:     11,
+			givenTotal:     1000,
+			expectedResult: true,
+		},
+		"Percentage_NotExceeded": {
+			givenThreshold: "10%",
+			givenCount:     10,
+			givenTotal:     100,
+			expectedResult: false,
+		},
+		"Percentage_Exceeded": {
+			givenThreshold: "10%",
+			givenCount:     11,
+			givenTotal:     100,
+			expectedResult: true,
+		},
+		"Percentage_EverythingDeleted": {
+			givenThreshold: "100%",
+			givenCount:     5,
+			givenTotal:     5,
+			expectedResult: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			threshold, err := ParseThreshold(tt.givenThreshold)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, threshold.Exceeded(tt.givenCount, tt.givenTotal))
+		})
+	}
+}
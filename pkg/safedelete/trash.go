@@ -0,0 +1,114 @@
+package safedelete
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+)
+
+// TrashDirName is the name of the trash directory within the base dir.
+const TrashDirName = ".trash"
+
+// logFileName is the name of the log file within the trash directory.
+const logFileName = "removed.log"
+
+// folderLayout is the time format of the subdirectories in the trash directory.
+const folderLayout = "2006-01-02T15-04-05"
+
+// Trash moves files into a trash directory instead of deleting them immediately.
+// Every removal is logged in a file in the trash directory.
+type Trash struct {
+	baseDir   string
+	retention time.Duration
+	now       time.Time
+}
+
+// NewTrash returns a new Trash for files within the given base dir.
+// If retention is 0, files are deleted immediately, but still logged.
+func NewTrash(baseDir string, retention time.Duration) *Trash {
+	return &Trash{
+		baseDir:   baseDir,
+		retention: retention,
+		now:       time.Now(),
+	}
+}
+
+// Dir returns the path of the trash directory.
+func (t *Trash) Dir() string {
+	return filepath.Join(t.baseDir, TrashDirName)
+}
+
+// Discard moves the file at the given slash-separated path relative to the base dir into the trash.
+// All files discarded by the same Trash end up in the same subdirectory, keeping their relative path.
+// The reason is written to the log.
+func (t *Trash) Discard(relPath, reason string) error {
+	source := filepath.Join(t.baseDir, filepath.FromSlash(relPath))
+	if _, err := os.Stat(source); err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	if t.retention == 0 {
+		if err := os.Remove(source); err != nil {
+			return fmt.Errorf("cannot remove file: %w", err)
+		}
+		return t.log("deleted", relPath, reason)
+	}
+	dest := filepath.Join(t.Dir(), t.now.Format(folderLayout), filepath.FromSlash(relPath))
+	if mkdirErr := os.MkdirAll(filepath.Dir(dest), os.ModePerm); mkdirErr != nil {
+		return fmt.Errorf("cannot create directory: %w", mkdirErr)
+	}
+	if renameErr := os.Rename(source, dest); renameErr != nil {
+		return fmt.Errorf("cannot move file to trash: %w", renameErr)
+	}
+	return t.log("trashed", relPath, reason)
+}
+
+// Purge permanently deletes the trashed files that are older than the retention.
+// It returns the paths of the deleted subdirectories.
+func (t *Trash) Purge() ([]string, error) {
+	entries, err := os.ReadDir(t.Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("cannot read trash directory: %w", err)
+	}
+	purged := make([]string, 0)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		trashedAt, parseErr := time.ParseInLocation(folderLayout, entry.Name(), time.Local)
+		if parseErr != nil {
+			// not created by us
+			continue
+		}
+		if t.now.Sub(trashedAt) <= t.retention {
+			continue
+		}
+		dir := filepath.Join(t.Dir(), entry.Name())
+		if removeErr := os.RemoveAll(dir); removeErr != nil {
+			return purged, fmt.Errorf("cannot purge trash: %w", removeErr)
+		}
+		if logErr := t.log("purged", entry.Name(), "retention expired"); logErr != nil {
+			return purged, logErr
+		}
+		purged = append(purged, dir)
+	}
+	return purged, nil
+}
+
+func (t *Trash) log(event, relPath, reason string) error {
+	if mkdirErr := os.MkdirAll(t.Dir(), os.ModePerm); mkdirErr != nil {
+		return fmt.Errorf("cannot create directory: %w", mkdirErr)
+	}
+	f, err := os.OpenFile(filepath.Join(t.Dir(), logFileName), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot open log file: %w", err)
+	}
+	defer f.Close()
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\t%s\n", time.Now().Format(time.RFC3339), event, relPath, reason)
+	return errors.Wrap(err, "cannot write log file")
+}
@@ -0,0 +1,45 @@
+package safedelete
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrash_Discard(t *testing.T) {
+	baseDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(baseDir, "archive", "2023"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(baseDir, "archive", "2023", "bill.pdf"), []byte{}, 0644))
+
+	trash := NewTrash(baseDir, time.Hour)
+	err := trash.Discard("archive/2023/bill.pdf", "deleted on server")
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(baseDir, "archive", "2023", "bill.pdf"))
+	assert.FileExists(t, filepath.Join(trash.Dir(), trash.now.Format(folderLayout), "archive", "2023", "bill.pdf"))
+	log, err := os.ReadFile(filepath.Join(trash.Dir(), logFileName))
+	assert.NoError(t, err)
+	assert.Contains(t, string(log), "\ttrashed\tarchive/2023/bill.pdf\tdeleted on server\n")
+}
+
+func TestTrash_Purge(t *testing.T) {
+	baseDir := t.TempDir()
+	trash := NewTrash(baseDir, 24*time.Hour)
+	expired := filepath.Join(trash.Dir(), trash.now.Add(-25*time.Hour).Format(folderLayout))
+	retained := filepath.Join(trash.Dir(), trash.now.Add(-23*time.Hour).Format(folderLayout))
+	unknown := filepath.Join(trash.Dir(), "unknown")
+	for _, dir := range []string{expired, retained, unknown} {
+		require.NoError(t, os.MkdirAll(dir, os.ModePerm))
+	}
+
+	purged, err := trash.Purge()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{expired}, purged)
+	assert.NoDirExists(t, expired)
+	assert.DirExists(t, retained)
+	assert.DirExists(t, unknown)
+}
@@ -0,0 +1,76 @@
+package syncstate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+)
+
+// Entry is a document that has been copied to the target instance.
+type Entry struct {
+	// TargetID is the ID of the document on the target instance.
+	TargetID int `json:"target_id"`
+	// Modified is the timestamp of the last change of the source document when it was synced.
+	Modified string `json:"modified,omitempty"`
+	// Checksum is the MD5 checksum of the original file.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+type stateContainer struct {
+	Source    string        `json:"source"`
+	Target    string        `json:"target"`
+	Documents map[int]Entry `json:"documents,omitempty"`
+}
+
+// State keeps track of the documents that have been synced between two instances, keyed by their ID on the source instance.
+// It is a simple wrapper around a JSON-based file.
+type State struct {
+	container stateContainer
+	filePath  string
+}
+
+// Open reads the state file at the given path.
+// A new state is returned if the file doesn't exist.
+// An error is returned if the file belongs to a different pair of instances, as the IDs wouldn't match.
+func Open(filePath, source, target string) (*State, error) {
+	container := stateContainer{Source: source, Target: target}
+	raw, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot open state file: %w", err)
+	}
+	if err == nil {
+		if parseErr := json.Unmarshal(raw, &container); parseErr != nil {
+			return nil, fmt.Errorf("cannot parse state file %s: %w", filePath, parseErr)
+		}
+	}
+	if container.Source != source || container.Target != target {
+		return nil, fmt.Errorf("state file %s belongs to the sync from %q to %q", filePath, container.Source, container.Target)
+	}
+	if container.Documents == nil {
+		container.Documents = map[int]Entry{}
+	}
+	return &State{container: container, filePath: filePath}, nil
+}
+
+// Get returns the entry of the given source document ID.
+// It returns false if the document hasn't been synced yet.
+func (s *State) Get(sourceID int) (Entry, bool) {
+	entry, found := s.container.Documents[sourceID]
+	return entry, found
+}
+
+// Put adds or updates the entry of the given source document ID.
+func (s *State) Put(sourceID int, entry Entry) {
+	s.container.Documents[sourceID] = entry
+}
+
+// Close saves the state.
+func (s *State) Close() error {
+	b, err := json.Marshal(s.container)
+	if err != nil {
+		return fmt.Errorf("cannot save state: %w", err)
+	}
+	return errors.Wrap(os.WriteFile(s.filePath, b, 0644), "cannot save state")
+}
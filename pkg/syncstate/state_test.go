@@ -0,0 +1,51 @@
+package syncstate
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "sync.json")
+	state, err := Open(filePath, "https://staging", "https://production")
+	require.NoError(t, err)
+	_, found := state.Get(1)
+	assert.False(t, found)
+
+	state.Put(1, Entry{TargetID: 10, Modified: "2024-01-01", Checksum: "abc"})
+	require.NoError(t, state.Close())
+
+	tests := map[string]struct {
+		givenSource   string
+		givenTarget   string
+		expectedEntry Entry
+		expectedError string
+	}{
+		"SameInstances": {
+			givenSource:   "https://staging",
+			givenTarget:   "https://production",
+			expectedEntry: Entry{TargetID: 10, Modified: "2024-01-01", Checksum: "abc"},
+		},
+		"OtherTarget": {
+			givenSource:   "https://staging",
+			givenTarget:   "https://other",
+			expectedError: `state file ` + filePath + ` belongs to the sync from "https://staging" to "https://production"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Open(filePath, tt.givenSource, tt.givenTarget)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			entry, found := result.Get(1)
+			assert.True(t, found)
+			assert.Equal(t, tt.expectedEntry, entry)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package taxonomy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is a type of entity managed by a Manifest.
+type Kind string
+
+const (
+	KindTag           Kind = "tag"
+	KindCorrespondent Kind = "correspondent"
+	KindDocumentType  Kind = "document type"
+	KindStoragePath   Kind = "storage path"
+	KindCustomField   Kind = "custom field"
+)
+
+// Kinds contains all kinds in the order they are applied.
+var Kinds = []Kind{KindTag, KindCorrespondent, KindDocumentType, KindStoragePath, KindCustomField}
+
+// Manifest is the desired state of the entities on the server.
+// A nil list means that the kind is not managed at all, whereas an empty list means that there are no entities of that kind.
+type Manifest struct {
+	Tags           []Spec `yaml:"tags"`
+	Correspondents []Spec `yaml:"correspondents"`
+	DocumentTypes  []Spec `yaml:"document_types"`
+	StoragePaths   []Spec `yaml:"storage_paths"`
+	CustomFields   []Spec `yaml:"custom_fields"`
+}
+
+// Spec is the desired state of a single entity.
+// Fields that are nil are not managed and left unchanged on the server.
+type Spec struct {
+	Name              string  `yaml:"name"`
+	Match             *string `yaml:"match,omitempty"`
+	MatchingAlgorithm *string `yaml:"matching_algorithm,omitempty"`
+	CaseInsensitive   *bool   `yaml:"case_insensitive,omitempty"`
+	// Color is only supported by tags.
+	Color *string `yaml:"color,omitempty"`
+	// InboxTag is only supported by tags.
+	InboxTag *bool `yaml:"inbox_tag,omitempty"`
+	// Path is only supported by storage paths.
+	Path *string `yaml:"path,omitempty"`
+	// DataType is only supported by custom fields.
+	DataType *string `yaml:"data_type,omitempty"`
+}
+
+// LoadManifest parses the manifest from the given YAML.
+// Unknown keys are rejected to catch typos.
+func LoadManifest(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(m); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("cannot parse manifest: %w", err)
+	}
+	for _, kind := range Kinds {
+		seen := map[string]bool{}
+		for _, spec := range m.Specs(kind) {
+			if spec.Name == "" {
+				return nil, fmt.Errorf("invalid %s: name is required", kind)
+			}
+			key := strings.ToLower(spec.Name)
+			if seen[key] {
+				return nil, fmt.Errorf("duplicate %s %q", kind, spec.Name)
+			}
+			seen[key] = true
+			if _, err := spec.Fields(kind); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// Specs returns the entities of the given kind.
+func (m *Manifest) Specs(kind Kind) []Spec {
+	switch kind {
+	case KindTag:
+		return m.Tags
+	case KindCorrespondent:
+		return m.Correspondents
+	case KindDocumentType:
+		return m.DocumentTypes
+	case KindStoragePath:
+		return m.StoragePaths
+	case KindCustomField:
+		return m.CustomFields
+	}
+	return nil
+}
+
+// Fields returns the managed fields of the entity keyed by their JSON name in the API.
+// It returns an error if a field isn't supported by the given kind.
+func (s Spec) Fields(kind Kind) (paperless.EntityPatch, error) {
+	fields := paperless.EntityPatch{"name": s.Name}
+	unsupported := make([]string, 0)
+	set := func(supported bool, key, field string, value any) {
+		if !supported {
+			unsupported = append(unsupported, key)
+			return
+		}
+		fields[field] = value
+	}
+	hasMatching := kind != KindCustomField
+	if s.Match != nil {
+		set(hasMatching, "match", "match", *s.Match)
+	}
+	if s.MatchingAlgorithm != nil {
+		algorithm, err := paperless.ParseMatchingAlgorithm(*s.MatchingAlgorithm)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", kind, s.Name, err)
+		}
+		set(hasMatching, "matching_algorithm", "matching_algorithm", algorithm)
+	}
+	if s.CaseInsensitive != nil {
+		set(hasMatching, "case_insensitive", "is_insensitive", *s.CaseInsensitive)
+	}
+	if s.Color != nil {
+		set(kind == KindTag, "color", "color", *s.Color)
+	}
+	if s.InboxTag != nil {
+		set(kind == KindTag, "inbox_tag", "is_inbox_tag", *s.InboxTag)
+	}
+	if s.Path != nil {
+		set(kind == KindStoragePath, "path", "path", *s.Path)
+	}
+	if s.DataType != nil {
+		set(kind == KindCustomField, "data_type", "data_type", *s.DataType)
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("invalid %s %q: unsupported field(s) %s", kind, s.Name, strings.Join(unsupported, ", "))
+	}
+	return fields, nil
+}
+
+// FromExport returns a manifest with all entities of the given export.
+func FromExport(m *export.Manifest) *Manifest {
+	result := &Manifest{
+		Tags:           make([]Spec, len(m.Tags)),
+		Correspondents: make([]Spec, len(m.Correspondents)),
+		DocumentTypes:  make([]Spec, len(m.DocumentTypes)),
+		StoragePaths:   make([]Spec, len(m.StoragePaths)),
+		CustomFields:   make([]Spec, len(m.CustomFields)),
+	}
+	for i, e := range m.Tags {
+		e := e
+		result.Tags[i] = specWithMatching(e.Name, e.Matching)
+		if e.Color != "" {
+			result.Tags[i].Color = &e.Color
+		}
+		result.Tags[i].InboxTag = &e.IsInboxTag
+	}
+	for i, e := range m.Correspondents {
+		result.Correspondents[i] = specWithMatching(e.Name, e.Matching)
+	}
+	for i, e := range m.DocumentTypes {
+		result.DocumentTypes[i] = specWithMatching(e.Name, e.Matching)
+	}
+	for i, e := range m.StoragePaths {
+		e := e
+		result.StoragePaths[i] = specWithMatching(e.Name, e.Matching)
+		result.StoragePaths[i].Path = &e.Path
+	}
+	for i, e := range m.CustomFields {
+		e := e
+		result.CustomFields[i] = Spec{Name: e.Name, DataType: &e.DataType}
+	}
+	return result
+}
+
+func specWithMatching(name string, matching paperless.Matching) Spec {
+	algorithm := matching.MatchingAlgorithm.String()
+	return Spec{Name: name, Match: &matching.Match, MatchingAlgorithm: &algorithm, CaseInsensitive: &matching.IsInsensitive}
+}
@@ -0,0 +1,104 @@
+package taxonomy
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	tests := map[string]struct {
+		givenYAML      string
+		expectedTags   []Spec
+		expectedFields paperless.EntityPatch
+		expectedError  string
+	}{
+		"Empty": {
+			givenYAML:    "",
+			expectedTags: nil,
+		},
+		"Tag": {
+			givenYAML: `
+tags:
+  - name: Invoice
+    match: invoice
+    matching_algorithm: any
+    color: "#ff0000"
+`,
+			expectedTags: []Spec{{Name: "Invoice", Match: ptr("invoice"), MatchingAlgorithm: ptr("any"), Color: ptr("#ff0000")}},
+			expectedFields: paperless.EntityPatch{
+				"name": "Invoice", "match": "invoice", "matching_algorithm": paperless.MatchAny, "color": "#ff0000",
+			},
+		},
+		"UnknownKey": {
+			givenYAML:     "tags:\n  - name: Invoice\n    colour: red\n",
+			expectedError: "cannot parse manifest: yaml: unmarshal errors:\n  line 3: field colour not found in type taxonomy.Spec",
+		},
+		"MissingName": {
+			givenYAML:     "correspondents:\n  - match: bank\n",
+			expectedError: "invalid correspondent: name is required",
+		},
+		"DuplicateName": {
+			givenYAML:     "tags:\n  - name: Invoice\n  - name: invoice\n",
+			expectedError: `duplicate tag "invoice"`,
+		},
+		"UnsupportedField": {
+			givenYAML:     "document_types:\n  - name: Letter\n    color: red\n    path: x\n",
+			expectedError: `invalid document type "Letter": unsupported field(s) color, path`,
+		},
+		"InvalidAlgorithm": {
+			givenYAML:     "tags:\n  - name: Invoice\n    matching_algorithm: magic\n",
+			expectedError: `invalid tag "Invoice": unknown matching algorithm "magic", must be one of [none, any, all, literal, regex, fuzzy, auto]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := LoadManifest(strings.NewReader(tt.givenYAML))
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTags, result.Tags)
+			if tt.expectedFields != nil {
+				fields, err := result.Tags[0].Fields(KindTag)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedFields, fields)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFromExport(t *testing.T) {
+	given := &export.Manifest{
+		Tags: []paperless.Tag{
+			{ID: 1, Name: "Invoice", Color: "#ff0000", Matching: paperless.Matching{Match: "bill", MatchingAlgorithm: paperless.MatchAny}},
+			{ID: 2, Name: "Inbox", IsInboxTag: true},
+		},
+		StoragePaths: []paperless.StoragePath{{ID: 3, Name: "By year", Path: "{created_year}/{title}"}},
+		CustomFields: []paperless.CustomField{{ID: 4, Name: "Amount", DataType: "monetary"}},
+	}
+	result := FromExport(given)
+
+	fields, err := result.Tags[0].Fields(KindTag)
+	require.NoError(t, err)
+	assert.Equal(t, paperless.EntityPatch{
+		"name": "Invoice", "match": "bill", "matching_algorithm": paperless.MatchAny, "is_insensitive": false, "color": "#ff0000", "is_inbox_tag": false,
+	}, fields)
+	fields, err = result.Tags[1].Fields(KindTag)
+	require.NoError(t, err)
+	assert.Equal(t, paperless.EntityPatch{
+		"name": "Inbox", "match": "", "matching_algorithm": paperless.MatchNone, "is_insensitive": false, "is_inbox_tag": true,
+	}, fields)
+	assert.Equal(t, ptr("{created_year}/{title}"), result.StoragePaths[0].Path)
+	assert.Equal(t, []Spec{{Name: "Amount", DataType: ptr("monetary")}}, result.CustomFields)
+	assert.Empty(t, result.Correspondents)
+}
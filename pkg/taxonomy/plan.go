@@ -0,0 +1,225 @@
+package taxonomy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+)
+
+// Action is what happens to an entity on the server.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Object is an existing entity on the server, reduced to its ID and the fields that can be managed.
+type Object struct {
+	ID     int
+	Fields paperless.EntityPatch
+}
+
+// Name returns the name of the entity.
+func (o Object) Name() string {
+	name, _ := o.Fields["name"].(string)
+	return name
+}
+
+// FieldDiff is a single changed field.
+type FieldDiff struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Change is a single operation that reconciles the server to the manifest.
+type Change struct {
+	Kind   Kind   `json:"kind"`
+	Action Action `json:"action"`
+	// ID of the existing entity, 0 if it is created.
+	ID   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+	// Fields to send to the server, nil for deletions.
+	Fields paperless.EntityPatch `json:"fields,omitempty"`
+	// Diffs contains the changed fields of an update.
+	Diffs []FieldDiff `json:"diffs,omitempty"`
+}
+
+// String returns a human-readable representation of the change in the form of a diff.
+func (c Change) String() string {
+	switch c.Action {
+	case ActionCreate:
+		keys := sortedKeys(c.Fields)
+		values := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if key != "name" {
+				values = append(values, fmt.Sprintf("%s=%v", key, c.Fields[key]))
+			}
+		}
+		return strings.TrimSpace(fmt.Sprintf("+ %s %q %s", c.Kind, c.Name, strings.Join(values, " ")))
+	case ActionUpdate:
+		values := make([]string, len(c.Diffs))
+		for i, diff := range c.Diffs {
+			values[i] = fmt.Sprintf("%s: %v -> %v", diff.Field, diff.Old, diff.New)
+		}
+		return fmt.Sprintf("~ %s %q (id %d) %s", c.Kind, c.Name, c.ID, strings.Join(values, ", "))
+	default:
+		return fmt.Sprintf("- %s %q (id %d)", c.Kind, c.Name, c.ID)
+	}
+}
+
+// Plan compares the desired entities of the given kind with the existing ones and returns the changes required to reconcile them.
+// Entities are matched by name, case-insensitively.
+// Existing entities that aren't in the manifest are only deleted if prune is true.
+func Plan(kind Kind, specs []Spec, existing []Object, prune bool) ([]Change, error) {
+	byName := map[string]Object{}
+	for _, obj := range existing {
+		byName[strings.ToLower(obj.Name())] = obj
+	}
+
+	changes := make([]Change, 0)
+	managed := map[int]bool{}
+	for _, spec := range specs {
+		fields, err := spec.Fields(kind)
+		if err != nil {
+			return nil, err
+		}
+		obj, exists := byName[strings.ToLower(spec.Name)]
+		if !exists {
+			if kind == KindCustomField && spec.DataType == nil {
+				return nil, fmt.Errorf("invalid %s %q: data_type is required to create it", kind, spec.Name)
+			}
+			changes = append(changes, Change{Kind: kind, Action: ActionCreate, Name: spec.Name, Fields: fields})
+			continue
+		}
+		managed[obj.ID] = true
+		diffs := make([]FieldDiff, 0)
+		for _, key := range sortedKeys(fields) {
+			if old := obj.Fields[key]; old != fields[key] {
+				diffs = append(diffs, FieldDiff{Field: key, Old: old, New: fields[key]})
+			}
+		}
+		if len(diffs) == 0 {
+			continue
+		}
+		patch := paperless.EntityPatch{}
+		for _, diff := range diffs {
+			if kind == KindCustomField && diff.Field == "data_type" {
+				return nil, fmt.Errorf("invalid %s %q: data type cannot be changed from %v to %v", kind, spec.Name, diff.Old, diff.New)
+			}
+			patch[diff.Field] = diff.New
+		}
+		changes = append(changes, Change{Kind: kind, Action: ActionUpdate, ID: obj.ID, Name: obj.Name(), Fields: patch, Diffs: diffs})
+	}
+
+	if prune {
+		for _, obj := range existing {
+			if !managed[obj.ID] {
+				changes = append(changes, Change{Kind: kind, Action: ActionDelete, ID: obj.ID, Name: obj.Name()})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// Fetch returns the existing entities of the given kind.
+func Fetch(ctx context.Context, clt *paperless.Client, kind Kind) ([]Object, error) {
+	switch kind {
+	case KindTag:
+		return toObjects(clt.QueryTags(ctx))(func(e paperless.Tag) Object {
+			return withMatching(e.ID, e.Name, e.Matching, paperless.EntityPatch{"color": e.Color, "is_inbox_tag": e.IsInboxTag})
+		})
+	case KindCorrespondent:
+		return toObjects(clt.QueryCorrespondents(ctx))(func(e paperless.Correspondent) Object {
+			return withMatching(e.ID, e.Name, e.Matching, paperless.EntityPatch{})
+		})
+	case KindDocumentType:
+		return toObjects(clt.QueryDocumentTypes(ctx))(func(e paperless.DocumentType) Object {
+			return withMatching(e.ID, e.Name, e.Matching, paperless.EntityPatch{})
+		})
+	case KindStoragePath:
+		return toObjects(clt.QueryStoragePaths(ctx))(func(e paperless.StoragePath) Object {
+			return withMatching(e.ID, e.Name, e.Matching, paperless.EntityPatch{"path": e.Path})
+		})
+	case KindCustomField:
+		return toObjects(clt.QueryCustomFields(ctx))(func(e paperless.CustomField) Object {
+			return Object{ID: e.ID, Fields: paperless.EntityPatch{"name": e.Name, "data_type": e.DataType}}
+		})
+	}
+	return nil, fmt.Errorf("unknown kind %q", kind)
+}
+
+// Apply executes the change on the server.
+func Apply(ctx context.Context, clt *paperless.Client, change Change) error {
+	var err error
+	switch change.Kind {
+	case KindTag:
+		err = apply(ctx, change, clt.CreateTag, clt.UpdateTag, clt.DeleteTag)
+	case KindCorrespondent:
+		err = apply(ctx, change, clt.CreateCorrespondent, clt.UpdateCorrespondent, clt.DeleteCorrespondent)
+	case KindDocumentType:
+		err = apply(ctx, change, clt.CreateDocumentType, clt.UpdateDocumentType, clt.DeleteDocumentType)
+	case KindStoragePath:
+		err = apply(ctx, change, clt.CreateStoragePath, clt.UpdateStoragePath, clt.DeleteStoragePath)
+	case KindCustomField:
+		err = apply(ctx, change, clt.CreateCustomField, clt.UpdateCustomField, clt.DeleteCustomField)
+	default:
+		err = fmt.Errorf("unknown kind %q", change.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot %s %s %q: %w", change.Action, change.Kind, change.Name, err)
+	}
+	return nil
+}
+
+func apply[T any](ctx context.Context, change Change,
+	create func(context.Context, paperless.EntityPatch) (*T, error),
+	update func(context.Context, int, paperless.EntityPatch) (*T, error),
+	del func(context.Context, int) error,
+) error {
+	var err error
+	switch change.Action {
+	case ActionCreate:
+		_, err = create(ctx, change.Fields)
+	case ActionUpdate:
+		_, err = update(ctx, change.ID, change.Fields)
+	case ActionDelete:
+		err = del(ctx, change.ID)
+	}
+	return err
+}
+
+func withMatching(id int, name string, matching paperless.Matching, fields paperless.EntityPatch) Object {
+	fields["name"] = name
+	fields["match"] = matching.Match
+	fields["matching_algorithm"] = matching.MatchingAlgorithm
+	fields["is_insensitive"] = matching.IsInsensitive
+	return Object{ID: id, Fields: fields}
+}
+
+func toObjects[T any](entities []T, err error) func(fn func(T) Object) ([]Object, error) {
+	return func(fn func(T) Object) ([]Object, error) {
+		if err != nil {
+			return nil, err
+		}
+		objects := make([]Object, len(entities))
+		for i, entity := range entities {
+			objects[i] = fn(entity)
+		}
+		return objects, nil
+	}
+}
+
+func sortedKeys(fields paperless.EntityPatch) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
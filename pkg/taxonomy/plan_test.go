@@ -0,0 +1,75 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	existing := []Object{
+		{ID: 1, Fields: paperless.EntityPatch{"name": "Invoice", "match": "", "matching_algorithm": paperless.MatchNone, "color": "#000000"}},
+		{ID: 2, Fields: paperless.EntityPatch{"name": "Unmanaged", "match": "", "matching_algorithm": paperless.MatchNone}},
+	}
+	tests := map[string]struct {
+		givenKind       Kind
+		givenSpecs      []Spec
+		givenExisting   []Object
+		givenPrune      bool
+		expectedChanges []string
+		expectedError   string
+	}{
+		"Unchanged": {
+			givenKind:       KindTag,
+			givenSpecs:      []Spec{{Name: "Invoice", Color: ptr("#000000")}},
+			givenExisting:   existing,
+			expectedChanges: []string{},
+		},
+		"Create": {
+			givenKind:       KindTag,
+			givenSpecs:      []Spec{{Name: "Receipt", MatchingAlgorithm: ptr("auto")}},
+			givenExisting:   existing,
+			expectedChanges: []string{`+ tag "Receipt" matching_algorithm=auto`},
+		},
+		"Update": {
+			givenKind:       KindTag,
+			givenSpecs:      []Spec{{Name: "invoice", Match: ptr("bill"), Color: ptr("#000000")}},
+			givenExisting:   existing,
+			expectedChanges: []string{`~ tag "Invoice" (id 1) match:  -> bill, name: Invoice -> invoice`},
+		},
+		"Prune": {
+			givenKind:       KindTag,
+			givenSpecs:      []Spec{{Name: "Invoice"}},
+			givenExisting:   existing,
+			givenPrune:      true,
+			expectedChanges: []string{`- tag "Unmanaged" (id 2)`},
+		},
+		"CustomFieldWithoutDataType": {
+			givenKind:     KindCustomField,
+			givenSpecs:    []Spec{{Name: "Amount"}},
+			expectedError: `invalid custom field "Amount": data_type is required to create it`,
+		},
+		"CustomFieldChangedDataType": {
+			givenKind:     KindCustomField,
+			givenSpecs:    []Spec{{Name: "Amount", DataType: ptr("monetary")}},
+			givenExisting: []Object{{ID: 1, Fields: paperless.EntityPatch{"name": "Amount", "data_type": "string"}}},
+			expectedError: `invalid custom field "Amount": data type cannot be changed from string to monetary`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := Plan(tt.givenKind, tt.givenSpecs, tt.givenExisting, tt.givenPrune)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			changes := make([]string, len(result))
+			for i, change := range result {
+				changes[i] = change.String()
+			}
+			assert.Equal(t, tt.expectedChanges, changes)
+		})
+	}
+}
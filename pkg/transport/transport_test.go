@@ -0,0 +1,189 @@
+package transport
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := map[string]struct {
+		givenName       string
+		expectedVersion uint16
+		expectedError   string
+	}{
+		"TLS12": {
+			givenName:       "1.2",
+			expectedVersion: tls.VersionTLS12,
+		},
+		"TLS13": {
+			givenName:       "1.3",
+			expectedVersion: tls.VersionTLS13,
+		},
+		"Unknown": {
+			givenName:     "TLSv1.2",
+			expectedError: `unknown TLS version "TLSv1.2", supported: [1.0, 1.1, 1.2, 1.3]`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseTLSVersion(tt.givenName)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedVersion, result)
+		})
+	}
+}
+
+func TestTLSOptions_Config(t *testing.T) {
+	invalidPEM := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(invalidPEM, []byte("not a certificate"), 0600))
+
+	tests := map[string]struct {
+		givenOptions  TLSOptions
+		expectedError string
+	}{
+		"Insecure": {
+			givenOptions: TLSOptions{InsecureSkipVerify: true, MinVersion: "1.3"},
+		},
+		"InvalidCAFile": {
+			givenOptions:  TLSOptions{CAFile: invalidPEM},
+			expectedError: "no certificates found in CA file " + invalidPEM,
+		},
+		"CertWithoutKey": {
+			givenOptions:  TLSOptions{CertFile: "client.pem"},
+			expectedError: "both client certificate and key are required for mutual TLS",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := tt.givenOptions.Config()
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.givenOptions.InsecureSkipVerify, result.InsecureSkipVerify)
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := map[string]struct {
+		givenHeaders    []string
+		expectedHeaders http.Header
+		expectedError   string
+	}{
+		"Valid": {
+			givenHeaders:    []string{"CF-Access-Client-Id: id", "X-Forwarded-User:jane", "X-Empty:"},
+			expectedHeaders: http.Header{"Cf-Access-Client-Id": {"id"}, "X-Forwarded-User": {"jane"}, "X-Empty": {""}},
+		},
+		"MissingColon": {
+			givenHeaders:  []string{"X-Token value"},
+			expectedError: `invalid header "X-Token value", expected "Name: value"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseHeaders(tt.givenHeaders)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHeaders, result)
+		})
+	}
+}
+
+func TestNewClient_Headers(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	clt, err := NewClient(Options{Headers: http.Header{"X-Proxy-Token": {"secret"}, "Authorization": {"Bearer proxy"}}})
+	require.NoError(t, err)
+	req, err := http.NewRequest("GET", server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Token paperless")
+	resp, err := clt.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	assert.Equal(t, "secret", received.Get("X-Proxy-Token"))
+	assert.Equal(t, "Token paperless", received.Get("Authorization"))
+	assert.Empty(t, req.Header.Get("X-Proxy-Token"), "original request modified")
+}
+
+func TestNewClient_ReadTimeout(t *testing.T) {
+	tests := map[string]struct {
+		givenDelay    time.Duration
+		expectedError bool
+	}{
+		"StalledServer": {
+			givenDelay:    time.Second,
+			expectedError: true,
+		},
+		"SlowDownload": {
+			// the whole download takes longer than the timeout, but data arrives in time
+			givenDelay: 30 * time.Millisecond,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for i := 0; i < 5; i++ {
+					select {
+					case <-r.Context().Done():
+						return
+					case <-time.After(tt.givenDelay):
+					}
+					_, _ = w.Write([]byte("data"))
+					w.(http.Flusher).Flush()
+				}
+			}))
+			defer server.Close()
+
+			clt, err := NewClient(Options{ReadTimeout: 100 * time.Millisecond})
+			require.NoError(t, err)
+			resp, err := clt.Get(server.URL)
+			if err == nil {
+				_, err = io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+			}
+			if tt.expectedError {
+				assert.ErrorContains(t, err, "timeout")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOptions_Anonymous(t *testing.T) {
+	opts := Options{
+		TLS:            TLSOptions{CAFile: "ca.pem", CertFile: "cert.pem", KeyFile: "key.pem", MinVersion: "1.2"},
+		Proxy:          "http://proxy:3128",
+		ConnectTimeout: time.Second,
+		Headers:        http.Header{"X-Proxy-Token": {"secret"}},
+	}
+	assert.Equal(t, Options{
+		TLS:            TLSOptions{CAFile: "ca.pem", MinVersion: "1.2"},
+		Proxy:          "http://proxy:3128",
+		ConnectTimeout: time.Second,
+	}, opts.Anonymous())
+	assert.Equal(t, "cert.pem", opts.TLS.CertFile, "original options modified")
+}
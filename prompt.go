@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+)
+
+// confirm asks the user to confirm the given message interactively.
+// It returns true without asking if skip is true.
+// An error is returned if confirmation is needed but the input isn't a terminal.
+func confirm(message string, skip bool) (bool, error) {
+	if skip {
+		return true, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("cannot ask for confirmation in non-interactive mode, use --%s to confirm", newYesFlag(nil).Name)
+	}
+	return pterm.DefaultInteractiveConfirm.Show(message)
+}
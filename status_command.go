@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/pterm/pterm"
+	"github.com/urfave/cli/v2"
+)
+
+// statusPermissionObjects are the objects for which the permissions are shown by the status command.
+var statusPermissionObjects = []string{"document", "tag", "correspondent", "documenttype", "storagepath", "customfield"}
+
+type StatusCommand struct {
+	cli.Command
+
+	PaperlessURL   string
+	PaperlessToken string
+	PaperlessUser  string
+}
+
+func newStatusCommand() *StatusCommand {
+	c := &StatusCommand{}
+	c.Command = cli.Command{
+		Name:    "status",
+		Aliases: []string{"ping"},
+		Usage:   "Checks the connection to the Paperless instance",
+		Description: `Checks whether the instance is reachable and the credentials are valid,
+and shows the server version, the permissions of the user and basic statistics.
+Exits with a non-zero code if a check fails, e.g. to use as health check.`,
+		Before: loadConfigFileFn,
+		Action: c.Action,
+		Flags: []cli.Flag{
+			newURLFlag(&c.PaperlessURL),
+			newUsernameFlag(&c.PaperlessUser),
+			newTokenFlag(&c.PaperlessToken),
+		},
+	}
+	return c
+}
+
+func (c *StatusCommand) Action(ctx *cli.Context) error {
+	if c.PaperlessURL == "" {
+		return showFlagError(ctx, fmt.Errorf(`Required flag %q not set`, newURLFlag(nil).Name))
+	}
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+
+	checks := &statusChecks{}
+	defer func() {
+		// the checks are printed even if one fails
+		_ = checks.print(ctx)
+	}()
+
+	user, err := clt.GetCurrentUser(ctx.Context)
+	statusErr := &paperless.StatusError{}
+	versionErr := &paperless.UnsupportedVersionError{}
+	switch {
+	case errors.As(err, &versionErr):
+		checks.add(true, "Server reachable", "url", c.PaperlessURL)
+		checks.add(false, "Server version not supported", "error", versionErr.Error())
+		return fmt.Errorf("server version not supported")
+	case errors.As(err, &statusErr) && statusErr.IsUnauthorized():
+		checks.add(true, "Server reachable", "url", c.PaperlessURL)
+		checks.add(false, "Authentication failed", "status", statusErr.Status)
+		return fmt.Errorf("authentication failed")
+	case err != nil:
+		checks.add(false, "Server not reachable", "url", c.PaperlessURL, "error", err.Error())
+		return fmt.Errorf("server not reachable")
+	}
+	checks.add(true, "Server reachable", "url", c.PaperlessURL)
+	checks.add(true, "Authenticated", "username", user.Username, "superuser", user.IsSuperuser)
+
+	version := clt.ServerVersion()
+	checks.add(true, "Server version", "version", version.Version, "api_version", version.APIVersion, "negotiated_api_version", clt.APIVersion())
+
+	permissions := make([]any, 0, len(statusPermissionObjects)*2)
+	for _, object := range statusPermissionObjects {
+		permissions = append(permissions, object, strings.Join(userPermissions(user, object), ","))
+	}
+	// without viewing documents, the user cannot do anything useful with this CLI
+	canViewDocuments := user.HasPermission("view_document")
+	checks.add(canViewDocuments, "Permissions", permissions...)
+
+	failed := !canViewDocuments
+	stats, err := clt.GetStatistics(ctx.Context)
+	if err != nil {
+		failed = true
+		checks.add(false, "Statistics not available", "error", err.Error())
+	} else {
+		checks.add(true, "Documents", "total", stats.DocumentsTotal, "inbox", stats.DocumentsInbox)
+	}
+	tasks, err := clt.QueryTasks(ctx.Context)
+	if err != nil {
+		failed = true
+		checks.add(false, "Tasks not available", "error", err.Error())
+	} else {
+		checks.add(true, "Tasks", countTasks(tasks)...)
+	}
+	if failed {
+		return fmt.Errorf("status check failed")
+	}
+	return nil
+}
+
+// userPermissions returns the actions the user is allowed to do with the given object, e.g. "view" and "add".
+func userPermissions(user *paperless.User, object string) []string {
+	allowed := make([]string, 0, 4)
+	for _, action := range []string{"view", "add", "change", "delete"} {
+		if user.HasPermission(action + "_" + object) {
+			allowed = append(allowed, action)
+		}
+	}
+	if len(allowed) == 0 {
+		return []string{"none"}
+	}
+	return allowed
+}
+
+// countTasks returns the number of tasks by status as key-value pairs, e.g. "pending", 2.
+func countTasks(tasks []paperless.Task) []any {
+	counts := map[paperless.TaskStatus]int{}
+	for _, task := range tasks {
+		counts[task.Status]++
+	}
+	keysAndValues := make([]any, 0)
+	for _, status := range []paperless.TaskStatus{paperless.TaskPending, paperless.TaskStarted, paperless.TaskRetry, paperless.TaskFailure} {
+		keysAndValues = append(keysAndValues, strings.ToLower(string(status)), counts[status])
+	}
+	return keysAndValues
+}
+
+// statusCheck is the result of a single check.
+type statusCheck struct {
+	OK      bool           `json:"ok"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+
+	// keysAndValues are the details in the order they are printed.
+	keysAndValues []any
+}
+
+// statusChecks collects the results of all checks in order.
+type statusChecks struct {
+	Checks []statusCheck `json:"checks"`
+}
+
+func (s *statusChecks) add(ok bool, message string, keysAndValues ...any) {
+	check := statusCheck{OK: ok, Message: message, Details: map[string]any{}, keysAndValues: keysAndValues}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		check.Details[fmt.Sprint(keysAndValues[i])] = keysAndValues[i+1]
+	}
+	s.Checks = append(s.Checks, check)
+}
+
+func (s *statusChecks) print(ctx *cli.Context) error {
+	return printResult(ctx, resultPrinter{
+		result: s,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"OK", "Check", "Details"}}
+			for _, check := range s.Checks {
+				data = append(data, []string{strconv.FormatBool(check.OK), check.Message, formatPairs(check.keysAndValues)})
+			}
+			return data
+		},
+		text: func(w io.Writer) error {
+			for _, check := range s.Checks {
+				printCheck(w, check.OK, check.Message, check.keysAndValues...)
+			}
+			return nil
+		},
+	})
+}
+
+// printCheck prints the result of a single check with the given key-value pairs in order.
+func printCheck(w io.Writer, ok bool, message string, keysAndValues ...any) {
+	if pairs := formatPairs(keysAndValues); pairs != "" {
+		message += " " + pterm.Gray("("+pairs+")")
+	}
+	printer := pterm.Success
+	if !ok {
+		printer = pterm.Error
+	}
+	printer.WithWriter(w).Println(message)
+}
+
+// formatPairs formats the key-value pairs in order, e.g. `key="value"`.
+func formatPairs(keysAndValues []any) string {
+	pairs := make([]string, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%v=%q", keysAndValues[i], fmt.Sprint(keysAndValues[i+1])))
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/errors"
+	"github.com/ccremer/paperless-cli/pkg/export"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/ccremer/paperless-cli/pkg/syncstate"
+	"github.com/ccremer/paperless-cli/pkg/taxonomy"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+type SyncCommand struct {
+	cli.Command
+
+	SourceProfile string
+	TargetProfile string
+	SourceURL     string
+	SourceToken   string
+	SourceUser    string
+	TargetURL     string
+	TargetToken   string
+	TargetUser    string
+
+	StateFile   string
+	TaskTimeout time.Duration
+	DryRun      bool
+}
+
+const syncDesc = `Copies all documents with their original files and metadata from the source to the target instance.
+Missing tags, correspondents, document types, storage paths and custom fields are created on the target by name.
+Documents that already exist on the target with the same checksum are not copied again.
+The copied documents are tracked in --%s, so that subsequent runs only copy new documents and update changed metadata.
+Documents that are deleted on the source are not deleted on the target.`
+
+// syncResult is the outcome of syncing a single document.
+type syncResult int
+
+const (
+	syncSkipped syncResult = iota
+	syncCopied
+	syncUpdated
+)
+
+func (r syncResult) String() string {
+	switch r {
+	case syncCopied:
+		return "copied"
+	case syncUpdated:
+		return "updated"
+	default:
+		return "skipped"
+	}
+}
+
+func newSyncCommand() *SyncCommand {
+	c := &SyncCommand{}
+	c.Command = cli.Command{
+		Name:        "sync",
+		Usage:       "Copies documents from one Paperless instance to another",
+		Description: fmt.Sprintf(syncDesc, newSyncStateFileFlag(nil).Name),
+		Before: func(ctx *cli.Context) error {
+			return loadConfigFile(ctx, map[string]string{
+				"source-": newSourceProfileFlag(nil).Name,
+				"target-": newTargetProfileFlag(nil).Name,
+			})
+		},
+		Action: actions(LogMetadata, c.Action),
+		Flags: []cli.Flag{
+			newSourceProfileFlag(&c.SourceProfile),
+			newTargetProfileFlag(&c.TargetProfile),
+			newSourceURLFlag(&c.SourceURL),
+			newSourceUsernameFlag(&c.SourceUser),
+			newSourceTokenFlag(&c.SourceToken),
+			newTargetURLFlag(&c.TargetURL),
+			newTargetUsernameFlag(&c.TargetUser),
+			newTargetTokenFlag(&c.TargetToken),
+			newSyncStateFileFlag(&c.StateFile),
+			newTaskTimeoutFlag(&c.TaskTimeout),
+			newDryRunFlag(&c.DryRun),
+		},
+	}
+	return c
+}
+
+func (c *SyncCommand) Action(ctx *cli.Context) error {
+	log := logr.FromContextOrDiscard(ctx.Context)
+	if c.SourceURL == "" || c.TargetURL == "" {
+		return showFlagError(ctx, fmt.Errorf("flags --%s and --%s are required", newSourceURLFlag(nil).Name, newTargetURLFlag(nil).Name))
+	}
+	if c.SourceURL == c.TargetURL {
+		return fmt.Errorf("source and target must be different instances")
+	}
+
+	// each instance obtains its own bearer token, if configured
+	source, err := newClient(ctx, c.SourceURL, c.SourceUser, c.SourceToken)
+	if err != nil {
+		return err
+	}
+	target, err := newClient(ctx, c.TargetURL, c.TargetUser, c.TargetToken)
+	if err != nil {
+		return err
+	}
+	state, err := syncstate.Open(c.StateFile, c.SourceURL, c.TargetURL)
+	if err != nil {
+		return err
+	}
+
+	entities, err := c.fetchEntities(ctx, source)
+	if err != nil {
+		return err
+	}
+	ids, err := createMissingEntities(ctx, target, entities, c.DryRun)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Getting list of documents")
+	documents, err := source.QueryDocuments(ctx.Context, paperless.QueryParams{
+		TruncateContent: true,
+		Ordering:        "id",
+		PageSize:        100,
+	})
+	if err != nil {
+		return errors.Wrap(err, "cannot query documents")
+	}
+
+	counts := map[syncResult]int{}
+	failed := 0
+	results := make([]documentResult, 0)
+	for _, doc := range documents {
+		result, syncErr := c.syncDocument(ctx, source, target, state, doc, ids)
+		if syncErr != nil {
+			log.Error(syncErr, "Could not sync document", "id", doc.ID, "title", doc.Title)
+			results = append(results, documentResult{ID: doc.ID, Title: doc.Title}.failed(syncErr))
+			failed++
+			continue
+		}
+		counts[result]++
+		if result == syncSkipped {
+			continue
+		}
+		entry, _ := state.Get(doc.ID)
+		results = append(results, documentResult{ID: doc.ID, Title: doc.Title, Action: result.String(), NewID: entry.TargetID})
+	}
+	if printErr := printDocumentResults(ctx, results); printErr != nil {
+		return printErr
+	}
+	if c.DryRun {
+		log.Info("Would sync documents", "copy", counts[syncCopied], "update", counts[syncUpdated], "skip", counts[syncSkipped])
+		return nil
+	}
+	log.Info("Synced documents", "copied", counts[syncCopied], "updated", counts[syncUpdated], "skipped", counts[syncSkipped], "failed", failed)
+	if closeErr := state.Close(); closeErr != nil {
+		return closeErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d documents could not be synced", failed, len(documents))
+	}
+	return nil
+}
+
+// fetchEntities returns all entities of the source instance in an otherwise empty manifest.
+func (c *SyncCommand) fetchEntities(ctx *cli.Context, source *paperless.Client) (*export.Manifest, error) {
+	m := &export.Manifest{}
+	var err error
+	if m.Tags, err = source.QueryTags(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query tags")
+	}
+	if m.Correspondents, err = source.QueryCorrespondents(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query correspondents")
+	}
+	if m.DocumentTypes, err = source.QueryDocumentTypes(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query document types")
+	}
+	if m.StoragePaths, err = source.QueryStoragePaths(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query storage paths")
+	}
+	if m.CustomFields, err = source.QueryCustomFields(ctx.Context); err != nil {
+		return nil, errors.Wrap(err, "cannot query custom fields")
+	}
+	return m, nil
+}
+
+// syncDocument copies a new document to the target or updates the metadata of a changed one.
+func (c *SyncCommand) syncDocument(ctx *cli.Context, source, target *paperless.Client, state *syncstate.State, doc paperless.Document, ids importIDs) (syncResult, error) {
+	log := logr.FromContextOrDiscard(ctx.Context)
+
+	if entry, found := state.Get(doc.ID); found {
+		if entry.Modified == doc.Modified {
+			return syncSkipped, nil
+		}
+		if c.DryRun {
+			log.Info("Would update changed document", "id", doc.ID, "title", doc.Title, "target_id", entry.TargetID)
+			return syncUpdated, nil
+		}
+		patch, err := makeSyncPatch(doc, ids)
+		if err != nil {
+			return syncSkipped, err
+		}
+		if _, err := target.UpdateDocument(ctx.Context, entry.TargetID, patch); err != nil {
+			return syncSkipped, errors.Wrap(err, "cannot update document %d on target", entry.TargetID)
+		}
+		entry.Modified = doc.Modified
+		state.Put(doc.ID, entry)
+		log.V(1).Info("Updated document", "id", doc.ID, "target_id", entry.TargetID)
+		return syncUpdated, nil
+	}
+
+	metadata, err := source.GetDocumentMetadata(ctx.Context, doc.ID)
+	if err != nil {
+		return syncSkipped, errors.Wrap(err, "cannot get checksum")
+	}
+	existing, err := target.QueryDocuments(ctx.Context, paperless.QueryParams{TruncateContent: true, Checksum: metadata.OriginalChecksum})
+	if err != nil {
+		return syncSkipped, errors.Wrap(err, "cannot query target for checksum")
+	}
+	if len(existing) > 0 {
+		log.V(1).Info("Document already exists on target", "id", doc.ID, "target_id", existing[0].ID)
+		// an empty modification time causes the metadata to be updated in the next run, as it may differ on the target
+		state.Put(doc.ID, syncstate.Entry{TargetID: existing[0].ID, Checksum: metadata.OriginalChecksum})
+		return syncSkipped, nil
+	}
+	if c.DryRun {
+		log.Info("Would copy document", "id", doc.ID, "title", doc.Title)
+		return syncCopied, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "paperless-sync-")
+	if err != nil {
+		return syncSkipped, fmt.Errorf("cannot create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	filePath, err := c.downloadOriginal(ctx, source, tmpDir, doc)
+	if err != nil {
+		return syncSkipped, err
+	}
+	newID, err := uploadWithMetadata(ctx, target, filePath, doc, ids, c.TaskTimeout)
+	if newID != 0 {
+		// an empty modification time causes the metadata to be updated in the next run if restoring failed
+		entry := syncstate.Entry{TargetID: newID, Checksum: metadata.OriginalChecksum}
+		if err == nil {
+			entry.Modified = doc.Modified
+		}
+		state.Put(doc.ID, entry)
+	}
+	if err != nil {
+		return syncSkipped, err
+	}
+	log.Info("Copied document", "id", doc.ID, "target_id", newID, "title", doc.Title)
+	return syncCopied, nil
+}
+
+// downloadOriginal downloads the original file of the document into the given dir, keeping its original file name.
+func (c *SyncCommand) downloadOriginal(ctx *cli.Context, source *paperless.Client, dir string, doc paperless.Document) (string, error) {
+	name := filepath.Base(doc.OriginalFileName)
+	if doc.OriginalFileName == "" {
+		name = fmt.Sprintf("document-%d", doc.ID)
+	}
+	filePath := filepath.Join(dir, name)
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("cannot open temporary file: %w", err)
+	}
+	downloadErr := source.DownloadDocument(ctx.Context, f, doc.ID, true)
+	closeErr := f.Close()
+	if downloadErr != nil {
+		return "", errors.Wrap(downloadErr, "cannot download document")
+	}
+	if closeErr != nil {
+		return "", fmt.Errorf("cannot write temporary file: %w", closeErr)
+	}
+	return filePath, nil
+}
+
+// makeSyncPatch returns all fields of the document that can be changed, with the IDs of related entities translated.
+func makeSyncPatch(doc paperless.Document, ids importIDs) (paperless.DocumentPatch, error) {
+	patch, err := makeImportPatch(doc, ids)
+	if err != nil {
+		return nil, err
+	}
+	patch["title"] = doc.Title
+	if created := doc.CreatedDate(); !created.IsZero() {
+		patch["created_date"] = created.Format(time.DateOnly)
+	}
+	patch["correspondent"] = nil
+	if doc.Correspondent != nil {
+		if patch["correspondent"], err = ids.get(taxonomy.KindCorrespondent, *doc.Correspondent); err != nil {
+			return nil, err
+		}
+	}
+	patch["document_type"] = nil
+	if doc.DocumentType != nil {
+		if patch["document_type"], err = ids.get(taxonomy.KindDocumentType, *doc.DocumentType); err != nil {
+			return nil, err
+		}
+	}
+	tags := make([]int, len(doc.Tags))
+	for i, tag := range doc.Tags {
+		if tags[i], err = ids.get(taxonomy.KindTag, tag); err != nil {
+			return nil, err
+		}
+	}
+	patch["tags"] = tags
+	for _, field := range []string{"archive_serial_number", "storage_path"} {
+		if _, set := patch[field]; !set {
+			patch[field] = nil
+		}
+	}
+	if _, set := patch["custom_fields"]; !set {
+		patch["custom_fields"] = []paperless.CustomFieldInstance{}
+	}
+	return patch, nil
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 
+	"github.com/ccremer/paperless-cli/pkg/hooks"
 	"github.com/ccremer/paperless-cli/pkg/paperless"
-	"github.com/ccremer/plogr"
 	"github.com/go-logr/logr"
 	"github.com/pterm/pterm"
 	"github.com/urfave/cli/v2"
@@ -24,6 +26,9 @@ type UploadCommand struct {
 	Correspondent     string
 	DocumentTags      cli.StringSlice
 	DeleteAfterUpload bool
+	DryRun            bool
+
+	hooks uploadHooks
 }
 
 func newUploadCommand() *UploadCommand {
@@ -41,7 +46,7 @@ func newUploadCommand() *UploadCommand {
 		}, loadConfigFileFn),
 		Action: actions(LogMetadata, c.Action),
 
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			newURLFlag(&c.PaperlessURL),
 			newUsernameFlag(&c.PaperlessUser),
 			newTokenFlag(&c.PaperlessToken),
@@ -51,7 +56,8 @@ func newUploadCommand() *UploadCommand {
 			newCorrespondentFlag(&c.Correspondent),
 			newTagFlag(&c.DocumentTags),
 			newDeleteAfterUploadFlag(&c.DeleteAfterUpload),
-		},
+			newDryRunFlag(&c.DryRun),
+		}, c.hooks.flags()...),
 		ArgsUsage: "[FILES...]",
 	}
 	return c
@@ -64,36 +70,112 @@ func (c *UploadCommand) Action(ctx *cli.Context) error {
 
 	if created := c.CreatedAt.Value(); created != nil {
 		params.Created = *created
-		log = log.WithValues("created", created.Format("2006-02-03"))
+		log = log.WithValues("created", created.Format("2006-01-02"))
 	}
 	params.DocumentType, params.Title, params.Correspondent = c.DocumentType, c.DocumentTitle, c.Correspondent
 	params.Tags = c.DocumentTags.Value()
-	log = log.WithValues("title", params.Title, "type", params.DocumentType, "tags", params.Tags)
+	log = log.WithValues("title", params.Title, "type", params.DocumentType, "correspondent", params.Correspondent, "tags", params.Tags)
 
-	clt := paperless.NewClient(c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	clt, err := newClient(ctx, c.PaperlessURL, c.PaperlessUser, c.PaperlessToken)
+	if err != nil {
+		return err
+	}
+	if err := c.hooks.setup(); err != nil {
+		return err
+	}
+	results := make([]uploadResult, 0, ctx.NArg())
 	for _, arg := range ctx.Args().Slice() {
+		result := uploadResult{File: arg, DryRun: c.DryRun}
+		if c.DryRun {
+			if _, statErr := os.Stat(arg); statErr != nil {
+				log.Error(statErr, "Could not read file")
+				results = append(results, result.failed(statErr))
+				continue
+			}
+			log.Info("Would upload file", "file", arg, "delete-after-upload", c.DeleteAfterUpload)
+			results = append(results, result.withParams(params))
+			continue
+		}
 		log.Info("Uploading file", "file", arg)
-		err := clt.Upload(ctx.Context, arg, params)
+		taskID, err := clt.UploadDocument(ctx.Context, arg, params)
 		if err != nil {
-			log.Error(err, "Could not upload file")
+			log.Error(err, "Could not upload file", "file", arg)
+			c.hooks.fire(ctx.Context, c.hooks.outcome(ctx.Context, clt, arg, "", err))
+			results = append(results, result.failed(err))
 			continue
 		}
-		pterm.Success.Println(plogr.DefaultFormatter("File uploaded", map[string]interface{}{
-			"file": arg,
-		}))
-		if c.DeleteAfterUpload {
-			c.deleteAfterUpload(arg)
+		result.TaskID = taskID
+		log.Info("File uploaded", "file", arg, "task_id", taskID)
+		payload := c.hooks.outcome(ctx.Context, clt, arg, taskID, nil)
+		c.hooks.fire(ctx.Context, payload)
+		result.DocumentID, result.Error = payload.DocumentID, payload.Error
+		switch payload.Event {
+		case hooks.EventDuplicate:
+			log.Info("File is a duplicate of an existing document", "file", arg, "reason", payload.Error)
+		case hooks.EventFailure:
+			log.Error(fmt.Errorf("%s", payload.Error), "Could not consume file", "file", arg, "task_id", taskID)
+		default:
+			if c.DeleteAfterUpload {
+				result.Deleted = c.deleteAfterUpload(ctx, arg)
+			}
 		}
+		results = append(results, result)
 	}
-	return nil
+	return printResult(ctx, resultPrinter{
+		result: results,
+		table: func() pterm.TableData {
+			data := pterm.TableData{{"File", "Task ID", "Document ID", "Deleted", "Error"}}
+			for _, r := range results {
+				documentID := ""
+				if r.DocumentID != 0 {
+					documentID = strconv.Itoa(r.DocumentID)
+				}
+				data = append(data, []string{r.File, r.TaskID, documentID, strconv.FormatBool(r.Deleted), r.Error})
+			}
+			return data
+		},
+		// the uploaded files are already logged
+		text: func(io.Writer) error { return nil },
+	})
+}
+
+// uploadResult is the outcome of uploading a single file.
+type uploadResult struct {
+	File   string `json:"file"`
+	TaskID string `json:"task_id,omitempty"`
+	// DocumentID is only known if waiting for the consumption.
+	DocumentID int    `json:"document_id,omitempty"`
+	Deleted    bool   `json:"deleted"`
+	DryRun     bool   `json:"dry_run,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// The metadata is only set in dry-run mode to show which metadata would be sent.
+	Title         string   `json:"title,omitempty"`
+	Created       string   `json:"created,omitempty"`
+	Correspondent string   `json:"correspondent,omitempty"`
+	DocumentType  string   `json:"document_type,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func (r uploadResult) withParams(params paperless.UploadParams) uploadResult {
+	if !params.Created.IsZero() {
+		r.Created = params.Created.Format("2006-01-02")
+	}
+	r.Title, r.Correspondent, r.DocumentType, r.Tags = params.Title, params.Correspondent, params.DocumentType, params.Tags
+	return r
+}
+
+func (r uploadResult) failed(err error) uploadResult {
+	r.Error = err.Error()
+	return r
 }
 
-func (c *UploadCommand) deleteAfterUpload(arg string) {
+// deleteAfterUpload removes the uploaded file and returns true if successful.
+func (c *UploadCommand) deleteAfterUpload(ctx *cli.Context, arg string) bool {
 	err := os.Remove(arg)
 	if err != nil {
-		pterm.Warning.Println(plogr.DefaultFormatter("File could not be deleted", map[string]interface{}{
-			"file":  arg,
-			"error": err,
-		}))
+		logr.FromContextOrDiscard(ctx.Context).Error(err, "File could not be deleted", "file", arg)
+		return false
 	}
+	return true
 }
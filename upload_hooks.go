@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ccremer/paperless-cli/pkg/hooks"
+	"github.com/ccremer/paperless-cli/pkg/paperless"
+	"github.com/go-logr/logr"
+	"github.com/urfave/cli/v2"
+)
+
+// webhookTimeout is the maximum duration of a single webhook request.
+const webhookTimeout = 30 * time.Second
+
+// uploadHooks contains the flag values for notifying about the results of uploads.
+type uploadHooks struct {
+	WebhookURLs cli.StringSlice
+	ExecHook    string
+	Events      cli.StringSlice
+	Wait        bool
+	TaskTimeout time.Duration
+
+	hooks *hooks.Hooks
+}
+
+func (h *uploadHooks) flags() []cli.Flag {
+	return []cli.Flag{
+		newWebhookURLFlag(&h.WebhookURLs),
+		newExecHookFlag(&h.ExecHook),
+		newHookEventFlag(&h.Events),
+		newWaitFlag(&h.Wait),
+		newTaskTimeoutFlag(&h.TaskTimeout),
+	}
+}
+
+// setup creates the hooks from the flag values.
+func (h *uploadHooks) setup() error {
+	events := make([]hooks.Event, 0)
+	for _, name := range h.Events.Value() {
+		event, err := hooks.ParseEvent(name)
+		if err != nil {
+			return err
+		}
+		events = append(events, event)
+	}
+	list := make([]hooks.Hook, 0)
+	client := &http.Client{Timeout: webhookTimeout}
+	for _, url := range h.WebhookURLs.Value() {
+		list = append(list, &hooks.Webhook{URL: url, Client: client})
+	}
+	if h.ExecHook != "" {
+		list = append(list, &hooks.ExecHook{Command: h.ExecHook})
+	}
+	h.hooks = hooks.New(events, list...)
+	return nil
+}
+
+// outcome returns the event of uploading the given file.
+// If waiting is enabled, the event is determined by the result of the consumption task.
+func (h *uploadHooks) outcome(ctx context.Context, clt *paperless.Client, file, taskID string, uploadErr error) hooks.Payload {
+	payload := hooks.Payload{Event: hooks.EventSuccess, File: file, TaskID: taskID}
+	if uploadErr != nil {
+		payload.Event, payload.Error = hooks.EventFailure, uploadErr.Error()
+		return payload
+	}
+	if !h.Wait {
+		return payload
+	}
+	logr.FromContextOrDiscard(ctx).V(1).Info("Waiting for document to be consumed", "file", file, "task_id", taskID)
+	waitCtx, cancel := context.WithTimeout(ctx, h.TaskTimeout)
+	defer cancel()
+	task, err := clt.WaitForTask(waitCtx, taskID, importPollInterval)
+	switch {
+	case task != nil && task.IsDuplicate():
+		payload.Event, payload.Error = hooks.EventDuplicate, task.Result
+	case err != nil:
+		payload.Event, payload.Error = hooks.EventFailure, err.Error()
+	default:
+		payload.DocumentID, _ = task.DocumentID()
+	}
+	return payload
+}
+
+// fire fires the hooks and logs errors.
+func (h *uploadHooks) fire(ctx context.Context, payload hooks.Payload) {
+	log := logr.FromContextOrDiscard(ctx)
+	if !h.hooks.Enabled() {
+		return
+	}
+	log.V(1).Info("Firing hooks", "event", payload.Event, "file", payload.File)
+	if err := h.hooks.Fire(ctx, payload); err != nil {
+		log.Error(fmt.Errorf("cannot notify about upload: %w", err), "Hook failed", "event", payload.Event, "file", payload.File)
+	}
+}